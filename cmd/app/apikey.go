@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	apikeyrepo "github.com/SanExpett/auto-catalog/internal/apikey/repository"
+	apikeyusecases "github.com/SanExpett/auto-catalog/internal/apikey/usecases"
+	"github.com/SanExpett/auto-catalog/pkg/config"
+	"github.com/SanExpett/auto-catalog/pkg/models"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const usageAPIKey = "usage: main apikey create <name> <scope,...> | list | revoke <id>"
+
+var ErrUsageAPIKey = errors.New(usageAPIKey)
+
+// runAPIKey is subcommand "apikey" which manages API keys of clients.
+func runAPIKey(ctx context.Context, configServer *config.Config, args []string) error {
+	if len(args) == 0 {
+		return ErrUsageAPIKey
+	}
+
+	pool, err := connect(ctx, configServer)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	apiKeyStorage, err := apikeyrepo.NewAPIKeyStorage(pool)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	apiKeyService, err := apikeyusecases.NewAPIKeyService(apiKeyStorage)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	switch args[0] {
+	case "create":
+		if len(args) != 3 {
+			return ErrUsageAPIKey
+		}
+
+		var scopes []models.Scope
+		for _, scope := range strings.Split(args[2], ",") {
+			scopes = append(scopes, models.Scope(strings.TrimSpace(scope)))
+		}
+
+		key, apiKey, err := apiKeyService.CreateAPIKey(ctx, args[1], scopes)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		fmt.Printf("created key id=%d, it is shown only once, store it now:\n%s\n", apiKey.ID, key)
+
+		return nil
+	case "list":
+		apiKeys, err := apiKeyService.GetAPIKeysList(ctx)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		return printAPIKeys(apiKeys)
+	case "revoke":
+		if len(args) != 2 {
+			return ErrUsageAPIKey
+		}
+
+		apiKeyID, err := strconv.ParseUint(args[1], 10, 64)
+		if err != nil {
+			return ErrUsageAPIKey
+		}
+
+		if err := apiKeyService.RevokeAPIKey(ctx, apiKeyID); err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		fmt.Printf("revoked key id=%d\n", apiKeyID)
+
+		return nil
+	default:
+		return ErrUsageAPIKey
+	}
+}
+
+func printAPIKeys(apiKeys []*models.APIKey) error {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0) //nolint:gomnd
+
+	fmt.Fprintln(writer, "ID\tNAME\tPREFIX\tSCOPES\tCREATED\tLAST USED\tREVOKED")
+
+	for _, apiKey := range apiKeys {
+		scopes := make([]string, 0, len(apiKey.Scopes))
+		for _, scope := range apiKey.Scopes {
+			scopes = append(scopes, string(scope))
+		}
+
+		fmt.Fprintf(writer, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", apiKey.ID, apiKey.Name, apiKey.Prefix,
+			strings.Join(scopes, ","), apiKey.CreatedAt.Format(time.DateTime), formatTime(apiKey.LastUsedAt),
+			formatTime(apiKey.RevokedAt))
+	}
+
+	return writer.Flush() //nolint:wrapcheck
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+
+	return t.Format(time.DateTime)
+}
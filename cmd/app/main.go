@@ -1,9 +1,27 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"github.com/SanExpett/auto-catalog/internal/server"
+	"github.com/SanExpett/auto-catalog/internal/server/repository"
 	"github.com/SanExpett/auto-catalog/pkg/config"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+)
+
+const usage = "usage: main [--storage=postgres|memory] [migrate up | down [N|all] | status] " +
+	"[apikey create <name> <scope,...> | list | revoke <id>] [user link <email> <person_id> | unlink <email>] " +
+	"[config print]"
+
+var (
+	ErrUsage         = errors.New(usage)
+	ErrMemoryStorage = errors.New("subcommands work only with postgres storage")
 )
 
 //	@title      AUTO-CATALOG project API
@@ -11,12 +29,74 @@ import (
 //	@description  This is a server of AUTO-CATALOG server.
 //
 // @Schemes http
-// @BasePath  /api/v1
+// @BasePath  /api
 func main() {
-	configServer := config.New()
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:]); err != nil {
+		fmt.Printf("Error in server: %s\n", err.Error())
+		stop()
+		os.Exit(1)
+	}
+}
 
-	srv := new(server.Server)
-	if err := srv.Run(configServer); err != nil {
-		fmt.Printf("Error in server: %s", err.Error())
+// run serves API without args, otherwise it runs subcommand. Flag --storage overrides setting STORAGE.
+func run(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("main", flag.ContinueOnError)
+	flags.String("storage", config.StoragePostgres, "storage of data: postgres or memory")
+
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("%w: %w", ErrUsage, err)
+	}
+
+	args = flags.Args()
+
+	// only flags given explicitly override config
+	overrides := make(map[string]string)
+	flags.Visit(func(f *flag.Flag) {
+		overrides[strings.ToUpper(f.Name)] = f.Value.String()
+	})
+
+	configServer, err := config.LoadWithOverrides(overrides)
+	if err != nil {
+		return err //nolint:wrapcheck
 	}
+
+	if len(args) == 0 {
+		srv := new(server.Server)
+
+		return srv.Run(ctx, configServer) //nolint:wrapcheck
+	}
+
+	switch args[0] {
+	case "migrate":
+		return runMigrate(ctx, configServer, args[1:])
+	case "apikey":
+		return runAPIKey(ctx, configServer, args[1:])
+	case "user":
+		return runUser(ctx, configServer, args[1:])
+	case "config":
+		if len(args) != 2 || args[1] != "print" {
+			return ErrUsage
+		}
+
+		return configServer.Print(os.Stdout) //nolint:wrapcheck
+	default:
+		return ErrUsage
+	}
+}
+
+// connect creates global logger and pool of connections for subcommands.
+func connect(ctx context.Context, configServer *config.Config) (*pgxpool.Pool, error) {
+	if configServer.MemoryStorage() {
+		return nil, ErrMemoryStorage
+	}
+
+	if _, err := server.NewLogger(configServer); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return repository.NewPgxPool(ctx, configServer.URLDataBase, configServer.DBMaxConns, //nolint:wrapcheck
+		configServer.DBMinConns)
 }
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/SanExpett/auto-catalog/internal/migrator"
+	"github.com/SanExpett/auto-catalog/pkg/config"
+	"math"
+	"strconv"
+)
+
+const usageMigrate = "usage: main migrate up | down [N|all] | status"
+
+var ErrUsageMigrate = errors.New(usageMigrate)
+
+// runMigrate is subcommand "migrate" which manages schema by embedded migrations.
+func runMigrate(ctx context.Context, configServer *config.Config, args []string) error {
+	if len(args) == 0 {
+		return ErrUsageMigrate
+	}
+
+	pool, err := connect(ctx, configServer)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	schemaMigrator, err := migrator.NewEmbeddedMigrator(pool)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := schemaMigrator.Up(ctx)
+		fmt.Printf("applied %d migrations\n", applied)
+
+		return err //nolint:wrapcheck
+	case "down":
+		steps, err := parseSteps(args[1:])
+		if err != nil {
+			return err
+		}
+
+		reverted, err := schemaMigrator.Down(ctx, steps)
+		fmt.Printf("reverted %d migrations\n", reverted)
+
+		return err //nolint:wrapcheck
+	case "status":
+		status, err := schemaMigrator.Status(ctx)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		fmt.Printf("version: %d\ndirty: %t\nlatest: %d\n", status.Version, status.Dirty, status.Latest)
+
+		for _, migration := range status.Pending {
+			fmt.Printf("pending: %d_%s\n", migration.Version, migration.Title)
+		}
+
+		return nil
+	default:
+		return ErrUsageMigrate
+	}
+}
+
+// parseSteps reads count of migrations to revert, one by default.
+func parseSteps(args []string) (int, error) {
+	if len(args) == 0 {
+		return 1, nil
+	}
+
+	if args[0] == "all" {
+		return math.MaxInt, nil
+	}
+
+	steps, err := strconv.Atoi(args[0])
+	if err != nil || steps <= 0 {
+		return 0, ErrUsageMigrate
+	}
+
+	return steps, nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	userrepo "github.com/SanExpett/auto-catalog/internal/user/repository"
+	userusecases "github.com/SanExpett/auto-catalog/internal/user/usecases"
+	"github.com/SanExpett/auto-catalog/pkg/config"
+	"strconv"
+)
+
+const usageUser = "usage: main user link <email> <person_id> | unlink <email>"
+
+var ErrUsageUser = errors.New(usageUser)
+
+// runUser is subcommand "user" which links users to people, linked user may access only data of that person.
+func runUser(ctx context.Context, configServer *config.Config, args []string) error {
+	var personID *uint64
+
+	switch {
+	case len(args) == 3 && args[0] == "link":
+		id, err := strconv.ParseUint(args[2], 10, 64)
+		if err != nil {
+			return ErrUsageUser
+		}
+
+		personID = &id
+	case len(args) == 2 && args[0] == "unlink":
+	default:
+		return ErrUsageUser
+	}
+
+	pool, err := connect(ctx, configServer)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	userStorage, err := userrepo.NewUserStorage(pool)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	sessionStorage, err := userrepo.NewSessionStorage(pool)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	userService, err := userusecases.NewUserService(userStorage, sessionStorage, configServer.SessionIdleTimeout,
+		configServer.SessionMaxAge)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if err := userService.LinkPerson(ctx, args[1], personID); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if personID == nil {
+		fmt.Printf("user %s is unlinked\n", args[1])
+	} else {
+		fmt.Printf("user %s is linked to person id=%d\n", args[1], *personID)
+	}
+
+	return nil
+}
@@ -0,0 +1,340 @@
+// Command speccheck fails if swag annotations of handlers and embedded docs/swagger.yaml disagree: operations,
+// types and requirement of parameters and schemas of responses are compared.
+// It is run by make spec-check and while building docker image.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/SanExpett/auto-catalog/docs"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const definitionsPrefix = "#/definitions/"
+
+var ErrNoModule = errors.New("go.mod has no module directive")
+
+// annotatedOperation keeps parameters by in:name and schemas of responses by status code, both are
+// described in the same form as describeParameter and describeSchema describe specification.
+type annotatedOperation struct {
+	source     string
+	parameters map[string]string
+	responses  map[string]string
+}
+
+// annotatedFile tells how type names in annotations of file are named in definitions of specification.
+type annotatedFile struct {
+	module string
+	// pkg is directory of file relative to root, types of it are named by it
+	pkg string
+	// imports are import paths by names of packages in file
+	imports map[string]string
+}
+
+func main() {
+	root := flag.String("root", ".", "path to root of project")
+	flag.Parse()
+
+	spec, err := docs.Parse()
+	if err != nil {
+		fmt.Printf("Error in speccheck: %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	module, err := readModule(filepath.Join(*root, "go.mod"))
+	if err != nil {
+		fmt.Printf("Error in speccheck: %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	annotated, err := collectAnnotations(*root, module, "internal")
+	if err != nil {
+		fmt.Printf("Error in speccheck: %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	problems := compare(spec, annotated)
+	for _, problem := range problems {
+		fmt.Println(problem)
+	}
+
+	if len(problems) != 0 {
+		fmt.Println("swagger annotations and docs/swagger.yaml disagree, run make swag")
+		os.Exit(1)
+	}
+}
+
+func readModule(goMod string) (string, error) {
+	file, err := os.Open(goMod)
+	if err != nil {
+		return "", err //nolint:wrapcheck
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if module, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "module "); ok {
+			return strings.TrimSpace(module), nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", err //nolint:wrapcheck
+	}
+
+	return "", ErrNoModule
+}
+
+func operationKey(method string, path string) string {
+	return strings.ToLower(method) + " " + path
+}
+
+func collectAnnotations(root string, module string, dir string) (map[string]annotatedOperation, error) {
+	annotated := make(map[string]annotatedOperation)
+
+	err := filepath.WalkDir(filepath.Join(root, dir), func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() || !strings.HasSuffix(filePath, ".go") || strings.HasSuffix(filePath, "_test.go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(token.NewFileSet(), filePath, nil, parser.ParseComments)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		pkg, err := filepath.Rel(root, filepath.Dir(filePath))
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		names := annotatedFile{module: module, pkg: filepath.ToSlash(pkg), imports: make(map[string]string)}
+
+		for _, spec := range file.Imports {
+			importPath, _ := strconv.Unquote(spec.Path.Value)
+
+			name := path.Base(importPath)
+			if spec.Name != nil {
+				name = spec.Name.Name
+			}
+
+			names.imports[name] = importPath
+		}
+
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Doc == nil {
+				continue
+			}
+
+			for key, operation := range parseFuncDoc(funcDecl.Doc, filePath+":"+funcDecl.Name.Name, names) {
+				annotated[key] = operation
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return annotated, nil
+}
+
+func parseFuncDoc(doc *ast.CommentGroup, source string, names annotatedFile) map[string]annotatedOperation {
+	operation := annotatedOperation{source: source, parameters: make(map[string]string),
+		responses: make(map[string]string)}
+
+	var routes []string
+
+	for _, line := range strings.Split(doc.Text(), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		switch fields[0] {
+		case "@Param":
+			if len(fields) >= 5 {
+				typ := primitiveType(fields[3])
+				if fields[2] == "body" {
+					typ = names.schema("object", fields[3])
+				}
+
+				operation.parameters[fields[2]+":"+fields[1]] = describeParameter(typ, fields[4] == "true")
+			}
+		case "@Success", "@Failure":
+			operation.responses[fields[1]] = ""
+
+			if len(fields) >= 4 && strings.HasPrefix(fields[2], "{") {
+				operation.responses[fields[1]] = names.schema(strings.Trim(fields[2], "{}"), fields[3])
+			}
+		case "@Router":
+			if len(fields) >= 3 {
+				routes = append(routes, operationKey(strings.Trim(fields[2], "[]"), fields[1]))
+			}
+		}
+	}
+
+	result := make(map[string]annotatedOperation, len(routes))
+	for _, route := range routes {
+		result[route] = operation
+	}
+
+	return result
+}
+
+// schema describes schema of annotation like {object} models.Car or {array} models.Car.
+func (f annotatedFile) schema(kind string, typ string) string {
+	switch {
+	case kind == "array":
+		return "[]" + f.definition(typ)
+	case strings.HasPrefix(typ, "map[string]"):
+		return "map[string]" + primitiveType(strings.TrimPrefix(typ, "map[string]"))
+	case kind == "string" || primitiveType(typ) != typ:
+		return primitiveType(typ)
+	default:
+		return f.definition(typ)
+	}
+}
+
+// definition returns name of definition of type in annotation: types of other packages of module are named
+// by import path, types of package of annotation by its directory.
+func (f annotatedFile) definition(typ string) string {
+	name, typeName, ok := strings.Cut(typ, ".")
+	if !ok {
+		return strings.ReplaceAll(f.pkg, "/", "_") + "." + typ
+	}
+
+	importPath, ok := f.imports[name]
+	if !ok {
+		return typ
+	}
+
+	return strings.NewReplacer("/", "_", ".", "_").Replace(importPath) + "." + typeName
+}
+
+func primitiveType(typ string) string {
+	switch typ {
+	case "int", "int64", "uint", "uint64", "integer":
+		return "integer"
+	case "bool", "boolean":
+		return "boolean"
+	default:
+		return typ
+	}
+}
+
+func describeParameter(typ string, required bool) string {
+	if required {
+		return typ + ", required"
+	}
+
+	return typ
+}
+
+func describeSchema(schema *docs.Schema) string {
+	switch {
+	case schema == nil:
+		return ""
+	case schema.Ref != "":
+		return strings.TrimPrefix(schema.Ref, definitionsPrefix)
+	case schema.Type == "array":
+		return "[]" + describeSchema(schema.Items)
+	case schema.AdditionalProperties != nil:
+		return "map[string]" + describeSchema(schema.AdditionalProperties)
+	default:
+		return schema.Type
+	}
+}
+
+func compare(spec *docs.Spec, annotated map[string]annotatedOperation) []string {
+	var problems []string
+
+	documented := make(map[string]bool)
+
+	for path, operations := range spec.Paths {
+		for method, operation := range operations {
+			key := operationKey(method, path)
+			documented[key] = true
+
+			annotation, ok := annotated[key]
+			if !ok {
+				problems = append(problems, fmt.Sprintf("%s: is in swagger.yaml, but has no @Router annotation", key))
+
+				continue
+			}
+
+			parameters := make(map[string]string, len(operation.Parameters))
+			for _, parameter := range operation.Parameters {
+				typ := parameter.Type
+				if parameter.In == "body" {
+					typ = describeSchema(parameter.Schema)
+				}
+
+				parameters[parameter.In+":"+parameter.Name] = describeParameter(typ, parameter.Required)
+			}
+
+			problems = append(problems, diff(key, annotation.source, "parameter", annotation.parameters, parameters)...)
+
+			responses := make(map[string]string, len(operation.Responses))
+			for code, response := range operation.Responses {
+				responses[code] = describeSchema(response.Schema)
+			}
+
+			problems = append(problems, diff(key, annotation.source, "response", annotation.responses, responses)...)
+		}
+	}
+
+	for key, annotation := range annotated {
+		if !documented[key] {
+			problems = append(problems, fmt.Sprintf("%s (%s): is annotated, but missing in swagger.yaml", key,
+				annotation.source))
+		}
+	}
+
+	sort.Strings(problems)
+
+	return problems
+}
+
+func diff(key string, source string, what string, annotated map[string]string, documented map[string]string,
+) []string {
+	var problems []string
+
+	for item, inSpec := range documented {
+		inAnnotations, ok := annotated[item]
+
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("%s (%s): %s %s is in swagger.yaml, but not annotated",
+				key, source, what, item))
+		case inAnnotations != inSpec:
+			problems = append(problems, fmt.Sprintf("%s (%s): %s %s is %q in annotations, but %q in swagger.yaml",
+				key, source, what, item, inAnnotations, inSpec))
+		}
+	}
+
+	for item := range annotated {
+		if _, ok := documented[item]; !ok {
+			problems = append(problems, fmt.Sprintf("%s (%s): %s %s is annotated, but missing in swagger.yaml",
+				key, source, what, item))
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,7 @@
+// Package db embeds sql migrations of database schema (see make create-migration), they are applied by migrator.
+package db
+
+import "embed"
+
+//go:embed migrations/*.sql
+var Migrations embed.FS
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS public."api_key";
+
+DROP SEQUENCE IF EXISTS api_key_id_seq;
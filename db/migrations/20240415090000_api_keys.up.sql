@@ -0,0 +1,14 @@
+CREATE SEQUENCE IF NOT EXISTS api_key_id_seq;
+
+CREATE TABLE IF NOT EXISTS public."api_key"
+(
+    id              BIGINT                   DEFAULT NEXTVAL('api_key_id_seq'::regclass) NOT NULL PRIMARY KEY,
+    name            TEXT                                                                 NOT NULL CHECK (name <> '')
+    CONSTRAINT max_len_api_key_name CHECK (LENGTH(name) <= 64),
+    prefix          TEXT                                                                 NOT NULL,
+    key_hash        BYTEA                                                                NOT NULL UNIQUE,
+    scopes          TEXT[]                                                               NOT NULL,
+    created_at      TIMESTAMP WITH TIME ZONE DEFAULT NOW()                               NOT NULL,
+    last_used_at    TIMESTAMP WITH TIME ZONE DEFAULT NULL,
+    revoked_at      TIMESTAMP WITH TIME ZONE DEFAULT NULL
+);
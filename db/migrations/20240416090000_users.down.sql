@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS public."session";
+DROP TABLE IF EXISTS public."user";
+
+DROP SEQUENCE IF EXISTS session_id_seq;
+DROP SEQUENCE IF EXISTS user_id_seq;
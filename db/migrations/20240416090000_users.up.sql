@@ -0,0 +1,23 @@
+CREATE SEQUENCE IF NOT EXISTS user_id_seq;
+CREATE SEQUENCE IF NOT EXISTS session_id_seq;
+
+CREATE TABLE IF NOT EXISTS public."user"
+(
+    id              BIGINT                   DEFAULT NEXTVAL('user_id_seq'::regclass)    NOT NULL PRIMARY KEY,
+    email           TEXT                                                                 NOT NULL UNIQUE CHECK (email <> '')
+    CONSTRAINT max_len_email CHECK (LENGTH(email) <= 256),
+    password_hash   TEXT                                                                 NOT NULL,
+    created_at      TIMESTAMP WITH TIME ZONE DEFAULT NOW()                               NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS public."session"
+(
+    id              BIGINT                   DEFAULT NEXTVAL('session_id_seq'::regclass) NOT NULL PRIMARY KEY,
+    user_id         BIGINT                                                               NOT NULL REFERENCES public."user" (id) ON DELETE CASCADE,
+    token_hash      BYTEA                                                                NOT NULL UNIQUE,
+    created_at      TIMESTAMP WITH TIME ZONE DEFAULT NOW()                               NOT NULL,
+    last_seen_at    TIMESTAMP WITH TIME ZONE DEFAULT NOW()                               NOT NULL,
+    expires_at      TIMESTAMP WITH TIME ZONE                                             NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS session_user_id_idx ON public."session" (user_id);
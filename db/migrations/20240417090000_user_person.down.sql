@@ -0,0 +1 @@
+ALTER TABLE public."user" DROP COLUMN IF EXISTS person_id;
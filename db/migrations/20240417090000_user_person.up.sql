@@ -0,0 +1,2 @@
+ALTER TABLE public."user"
+    ADD COLUMN IF NOT EXISTS person_id BIGINT DEFAULT NULL UNIQUE REFERENCES public."people" (id) ON DELETE SET NULL;
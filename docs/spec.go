@@ -0,0 +1,78 @@
+// Package docs embeds swagger specification generated by swag from handlers annotations (see make swag).
+package docs
+
+import (
+	_ "embed"
+	"fmt"
+	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed swagger.yaml
+var SwaggerYAML []byte
+
+type Spec struct {
+	Swagger     string                          `yaml:"swagger"`
+	BasePath    string                          `yaml:"basePath"`
+	Info        Info                            `yaml:"info"`
+	Paths       map[string]map[string]Operation `yaml:"paths"`
+	Definitions map[string]*Schema              `yaml:"definitions"`
+}
+
+type Info struct {
+	Title       string `yaml:"title"`
+	Description string `yaml:"description"`
+	Version     string `yaml:"version"`
+}
+
+type Operation struct {
+	Summary     string              `yaml:"summary"`
+	Description string              `yaml:"description"`
+	Tags        []string            `yaml:"tags"`
+	Consumes    []string            `yaml:"consumes"`
+	Produces    []string            `yaml:"produces"`
+	Parameters  []Parameter         `yaml:"parameters"`
+	Responses   map[string]Response `yaml:"responses"`
+}
+
+type Parameter struct {
+	Name        string  `yaml:"name"`
+	In          string  `yaml:"in"`
+	Description string  `yaml:"description"`
+	Type        string  `yaml:"type"`
+	Required    bool    `yaml:"required"`
+	Schema      *Schema `yaml:"schema"`
+}
+
+type Response struct {
+	Description string            `yaml:"description"`
+	Schema      *Schema           `yaml:"schema"`
+	Headers     map[string]Header `yaml:"headers"`
+}
+
+type Header struct {
+	Description string `yaml:"description"`
+	Type        string `yaml:"type"`
+}
+
+type Schema struct {
+	Ref        string             `yaml:"$ref"`
+	Type       string             `yaml:"type"`
+	Format     string             `yaml:"format"`
+	Required   []string           `yaml:"required"`
+	Properties map[string]*Schema `yaml:"properties"`
+	Items      *Schema            `yaml:"items"`
+	// AdditionalProperties describes values of objects which are maps, e.g. limits by classes of requests
+	AdditionalProperties *Schema `yaml:"additionalProperties"`
+}
+
+// Parse parses embedded specification.
+func Parse() (*Spec, error) {
+	spec := &Spec{} //nolint:exhaustruct
+
+	if err := yaml.Unmarshal(SwaggerYAML, spec); err != nil {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	return spec, nil
+}
@@ -0,0 +1,179 @@
+package delivery
+
+import (
+	"encoding/json"
+	"github.com/SanExpett/auto-catalog/internal/server/delivery"
+	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
+	"github.com/SanExpett/auto-catalog/pkg/my_logger"
+	"github.com/SanExpett/auto-catalog/pkg/ratelimit"
+	"github.com/SanExpett/auto-catalog/pkg/utils"
+	"go.uber.org/zap/zapcore"
+	"net/http"
+	"sort"
+)
+
+var (
+	ErrDecodeLogLevel  = myerrors.NewError("log_level_decode")
+	ErrDecodeRateLimit = myerrors.NewError("rate_limit_decode")
+)
+
+// AdminHandler serves endpoints for operators of service, they are protected by API keys with admin scope.
+type AdminHandler struct {
+	limiter *ratelimit.Limiter
+}
+
+func NewAdminHandler(limiter *ratelimit.Limiter) (*AdminHandler, error) {
+	return &AdminHandler{limiter: limiter}, nil
+}
+
+// GetLogLevelHandler sends current level of logs.
+//
+//	@Summary    get log level
+//	@Tags Admin
+//	@Produce    json,application/problem+json
+//	@Success    200  {object} LogLevelResponse
+//	@Failure    401  {object} delivery.Problem "Unauthorized"
+//	@Failure    403  {object} delivery.Problem "Forbidden"
+//	@Failure    405  {object} delivery.Problem "Method Not Allowed"
+//	@Failure    500  {object} delivery.Problem "Internal Server Error"
+//	@Router      /admin/log/level [get]
+func (a *AdminHandler) GetLogLevelHandler(w http.ResponseWriter, r *http.Request) {
+	logger := my_logger.FromContext(r.Context())
+
+	delivery.SendOkResponse(w, r, logger,
+		NewLogLevelResponse(delivery.StatusResponseSuccessful, my_logger.Level().String()))
+}
+
+// SetLogLevelHandler changes level of logs without restart, body is {"level": "debug"}.
+//
+//	@Summary    set log level
+//	@Tags Admin
+//	@Accept      json
+//	@Produce    json,application/problem+json
+//	@Param      level  body LogLevel true  "new level: debug, info, warn or error"
+//	@Success    200  {object} LogLevelResponse
+//	@Failure    400  {object} delivery.Problem "Bad Request"
+//	@Failure    401  {object} delivery.Problem "Unauthorized"
+//	@Failure    403  {object} delivery.Problem "Forbidden"
+//	@Failure    405  {object} delivery.Problem "Method Not Allowed"
+//	@Failure    422  {object} delivery.Problem "Unprocessable Entity"
+//	@Failure    500  {object} delivery.Problem "Internal Server Error"
+//	@Router      /admin/log/level [put]
+func (a *AdminHandler) SetLogLevelHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := my_logger.FromContext(ctx)
+
+	logLevel := &LogLevel{}
+	if err := json.NewDecoder(r.Body).Decode(logLevel); err != nil {
+		delivery.HandleErr(w, r, logger, ErrDecodeLogLevel)
+
+		return
+	}
+
+	newLevel, err := zapcore.ParseLevel(logLevel.Level)
+	if err != nil || newLevel > zapcore.ErrorLevel {
+		delivery.HandleErr(w, r, logger, myerrors.NewValidationErrors([]myerrors.FieldError{
+			myerrors.NewFieldError("level", utils.CodeInvalid, "log_level_invalid", logLevel.Level),
+		}))
+
+		return
+	}
+
+	oldLevel := my_logger.Level()
+	my_logger.SetLevel(newLevel)
+
+	delivery.SendOkResponse(w, r, logger, NewLogLevelResponse(delivery.StatusResponseSuccessful, newLevel.String()))
+	// warn level keeps change visible unless logs are limited to errors
+	logger.Warnf("in SetLogLevelHandler: log level changed from %s to %s", oldLevel, newLevel)
+}
+
+// GetRateLimitsHandler sends current rate limits of classes of requests.
+//
+//	@Summary    get rate limits
+//	@Tags Admin
+//	@Produce    json,application/problem+json
+//	@Success    200  {object} RateLimitsResponse
+//	@Failure    401  {object} delivery.Problem "Unauthorized"
+//	@Failure    403  {object} delivery.Problem "Forbidden"
+//	@Failure    405  {object} delivery.Problem "Method Not Allowed"
+//	@Failure    500  {object} delivery.Problem "Internal Server Error"
+//	@Router      /admin/ratelimit [get]
+func (a *AdminHandler) GetRateLimitsHandler(w http.ResponseWriter, r *http.Request) {
+	logger := my_logger.FromContext(r.Context())
+
+	delivery.SendOkResponse(w, r, logger, NewRateLimitsResponse(delivery.StatusResponseSuccessful, a.limiter.Limits()))
+}
+
+// SetRateLimitsHandler changes rate limits of given classes without restart, body is {"read": "100/1m", "write": "off"}.
+// Limits are changed only if all of them are valid.
+//
+//	@Summary    set rate limits
+//	@Tags Admin
+//	@Accept      json
+//	@Produce    json,application/problem+json
+//	@Param      limits  body map[string]string true  "limits by classes of requests like 100/1m or off"
+//	@Success    200  {object} RateLimitsResponse
+//	@Failure    400  {object} delivery.Problem "Bad Request"
+//	@Failure    401  {object} delivery.Problem "Unauthorized"
+//	@Failure    403  {object} delivery.Problem "Forbidden"
+//	@Failure    405  {object} delivery.Problem "Method Not Allowed"
+//	@Failure    422  {object} delivery.Problem "Unprocessable Entity"
+//	@Failure    500  {object} delivery.Problem "Internal Server Error"
+//	@Router      /admin/ratelimit [put]
+func (a *AdminHandler) SetRateLimitsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := my_logger.FromContext(ctx)
+
+	rawLimits := make(map[ratelimit.Class]string)
+	if err := json.NewDecoder(r.Body).Decode(&rawLimits); err != nil {
+		delivery.HandleErr(w, r, logger, ErrDecodeRateLimit)
+
+		return
+	}
+
+	oldLimits := a.limiter.Limits()
+	newLimits := make(map[ratelimit.Class]ratelimit.Limit, len(rawLimits))
+
+	var fieldErrors []myerrors.FieldError
+
+	for class, rawLimit := range rawLimits {
+		if _, ok := oldLimits[class]; !ok {
+			fieldErrors = append(fieldErrors, myerrors.NewFieldError(string(class), utils.CodeInvalid,
+				"rate_limit_class_unknown"))
+
+			continue
+		}
+
+		var limit ratelimit.Limit
+		if err := limit.UnmarshalText([]byte(rawLimit)); err != nil {
+			fieldErrors = append(fieldErrors, myerrors.NewFieldError(string(class), utils.CodeInvalidFormat,
+				"rate_limit_invalid", rawLimit))
+
+			continue
+		}
+
+		newLimits[class] = limit
+	}
+
+	if len(fieldErrors) != 0 {
+		sort.Slice(fieldErrors, func(i, j int) bool {
+			return fieldErrors[i].Field < fieldErrors[j].Field
+		})
+		delivery.HandleErr(w, r, logger, myerrors.NewValidationErrors(fieldErrors))
+
+		return
+	}
+
+	for class, limit := range newLimits {
+		if err := a.limiter.SetLimit(class, limit); err != nil {
+			delivery.HandleErr(w, r, logger, err)
+
+			return
+		}
+
+		logger.Warnf("in SetRateLimitsHandler: %s rate limit changed from %s to %s",
+			class, oldLimits[class], limit)
+	}
+
+	delivery.SendOkResponse(w, r, logger, NewRateLimitsResponse(delivery.StatusResponseSuccessful, a.limiter.Limits()))
+}
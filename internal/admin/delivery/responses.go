@@ -0,0 +1,31 @@
+package delivery
+
+import "github.com/SanExpett/auto-catalog/pkg/ratelimit"
+
+type LogLevel struct {
+	Level string `json:"level"`
+}
+
+type LogLevelResponse struct {
+	Status int      `json:"status"`
+	Body   LogLevel `json:"body"`
+}
+
+func NewLogLevelResponse(status int, level string) *LogLevelResponse {
+	return &LogLevelResponse{
+		Status: status,
+		Body:   LogLevel{Level: level},
+	}
+}
+
+type RateLimitsResponse struct {
+	Status int                                 `json:"status"`
+	Body   map[ratelimit.Class]ratelimit.Limit `json:"body"`
+}
+
+func NewRateLimitsResponse(status int, limits map[ratelimit.Class]ratelimit.Limit) *RateLimitsResponse {
+	return &RateLimitsResponse{
+		Status: status,
+		Body:   limits,
+	}
+}
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/SanExpett/auto-catalog/internal/server/repository"
+	"github.com/SanExpett/auto-catalog/pkg/metrics"
+	"github.com/SanExpett/auto-catalog/pkg/models"
+	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
+	"github.com/SanExpett/auto-catalog/pkg/my_logger"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+var (
+	ErrAPIKeyNotFound = myerrors.NewNotFoundError("api_key_not_found")
+
+	// APIKeyConstraints are constraints of table api_key, memory storage checks them too
+	APIKeyConstraints = map[string]repository.Constraint{ //nolint:gochecknoglobals
+		"api_key_name_check":   {Field: "name", Key: "name_empty"},
+		"max_len_api_key_name": {Field: "name", Key: "name_too_long"},
+	}
+)
+
+const (
+	// lastUsedPrecision limits writes of last usage time to one per key in this period
+	lastUsedPrecision = time.Minute
+)
+
+type APIKeyStorage struct {
+	pool *pgxpool.Pool
+}
+
+func NewAPIKeyStorage(pool *pgxpool.Pool) (*APIKeyStorage, error) {
+	return &APIKeyStorage{pool: pool}, nil
+}
+
+func (a *APIKeyStorage) AddAPIKey(ctx context.Context, name string, prefix string, keyHash []byte,
+	scopes []models.Scope,
+) (*models.APIKey, error) {
+	defer metrics.ObserveQuery("api_key", "AddAPIKey", time.Now())
+
+	SQLInsertAPIKey := `INSERT INTO public."api_key"(name, prefix, key_hash, scopes) VALUES($1, $2, $3, $4)
+		RETURNING id, created_at`
+
+	apiKey := &models.APIKey{Name: name, Prefix: prefix, Scopes: scopes} //nolint:exhaustruct
+
+	err := a.pool.QueryRow(ctx, SQLInsertAPIKey, name, prefix, keyHash, scopesToStrings(scopes)).
+		Scan(&apiKey.ID, &apiKey.CreatedAt)
+	if err != nil {
+		my_logger.FromContext(ctx).Errorln(err)
+
+		return nil, fmt.Errorf(myerrors.ErrTemplate, repository.MapPgError(err, APIKeyConstraints))
+	}
+
+	return apiKey, nil
+}
+
+// GetAPIKeyByHash returns not revoked key with hash keyHash.
+func (a *APIKeyStorage) GetAPIKeyByHash(ctx context.Context, keyHash []byte) (*models.APIKey, error) {
+	defer metrics.ObserveQuery("api_key", "GetAPIKeyByHash", time.Now())
+
+	SQLSelectAPIKey := `SELECT id, name, prefix, scopes, created_at, last_used_at, revoked_at
+		FROM public."api_key" WHERE key_hash=$1 AND revoked_at IS NULL`
+
+	apiKey, err := scanAPIKey(a.pool.QueryRow(ctx, SQLSelectAPIKey, keyHash))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf(myerrors.ErrTemplate, ErrAPIKeyNotFound)
+		}
+
+		my_logger.FromContext(ctx).Errorln(err)
+
+		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	return apiKey, nil
+}
+
+func (a *APIKeyStorage) GetAPIKeysList(ctx context.Context) ([]*models.APIKey, error) {
+	defer metrics.ObserveQuery("api_key", "GetAPIKeysList", time.Now())
+
+	SQLSelectAPIKeys := `SELECT id, name, prefix, scopes, created_at, last_used_at, revoked_at
+		FROM public."api_key" ORDER BY id`
+
+	rows, err := a.pool.Query(ctx, SQLSelectAPIKeys)
+	if err != nil {
+		my_logger.FromContext(ctx).Errorln(err)
+
+		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+	defer rows.Close()
+
+	apiKeys := make([]*models.APIKey, 0)
+
+	for rows.Next() {
+		apiKey, err := scanAPIKey(rows)
+		if err != nil {
+			my_logger.FromContext(ctx).Errorln(err)
+
+			return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+		}
+
+		apiKeys = append(apiKeys, apiKey)
+	}
+
+	if err := rows.Err(); err != nil {
+		my_logger.FromContext(ctx).Errorln(err)
+
+		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	return apiKeys, nil
+}
+
+func (a *APIKeyStorage) RevokeAPIKey(ctx context.Context, apiKeyID uint64) error {
+	defer metrics.ObserveQuery("api_key", "RevokeAPIKey", time.Now())
+
+	SQLRevokeAPIKey := `UPDATE public."api_key" SET revoked_at=NOW() WHERE id=$1 AND revoked_at IS NULL`
+
+	result, err := a.pool.Exec(ctx, SQLRevokeAPIKey, apiKeyID)
+	if err != nil {
+		my_logger.FromContext(ctx).Errorf("error with APIKeyId=%d: %+v", apiKeyID, err)
+
+		return fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf(myerrors.ErrTemplate, ErrAPIKeyNotFound)
+	}
+
+	return nil
+}
+
+// TouchAPIKey records that key is used now, time is updated not more often than lastUsedPrecision.
+func (a *APIKeyStorage) TouchAPIKey(ctx context.Context, apiKeyID uint64) error {
+	defer metrics.ObserveQuery("api_key", "TouchAPIKey", time.Now())
+
+	SQLUpdateLastUsedAt := `UPDATE public."api_key" SET last_used_at=NOW()
+		WHERE id=$1 AND (last_used_at IS NULL OR last_used_at < NOW() - $2::interval)`
+
+	if _, err := a.pool.Exec(ctx, SQLUpdateLastUsedAt, apiKeyID, lastUsedPrecision); err != nil {
+		my_logger.FromContext(ctx).Errorf("error with APIKeyId=%d: %+v", apiKeyID, err)
+
+		return fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	return nil
+}
+
+func scanAPIKey(row pgx.Row) (*models.APIKey, error) {
+	apiKey := &models.APIKey{} //nolint:exhaustruct
+
+	var scopes []string
+
+	if err := row.Scan(&apiKey.ID, &apiKey.Name, &apiKey.Prefix, &scopes, &apiKey.CreatedAt,
+		&apiKey.LastUsedAt, &apiKey.RevokedAt); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	apiKey.Scopes = make([]models.Scope, 0, len(scopes))
+	for _, scope := range scopes {
+		apiKey.Scopes = append(apiKey.Scopes, models.Scope(scope))
+	}
+
+	return apiKey, nil
+}
+
+func scopesToStrings(scopes []models.Scope) []string {
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		result = append(result, string(scope))
+	}
+
+	return result
+}
@@ -0,0 +1,151 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	apikeyrepo "github.com/SanExpett/auto-catalog/internal/apikey/repository"
+	"github.com/SanExpett/auto-catalog/pkg/debounce"
+	"github.com/SanExpett/auto-catalog/pkg/models"
+	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
+	"github.com/SanExpett/auto-catalog/pkg/my_logger"
+	"github.com/SanExpett/auto-catalog/pkg/utils"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var _ IAPIKeyStorage = (*apikeyrepo.APIKeyStorage)(nil)
+
+var (
+	ErrInvalidAPIKey = myerrors.NewUnauthorizedError("api_key_invalid")
+)
+
+const (
+	// keyPrefix marks keys of this service, so leaked keys are easy to find by secret scanners
+	keyPrefix = "ac_"
+	// keySecretBytes is count of random bytes in key, it makes brute force impossible and lets store
+	// keys hashed by fast sha256 instead of password hashes
+	keySecretBytes = 32
+	// shownPrefixLen is length of key start which is stored in plain text to tell keys apart
+	shownPrefixLen = 10
+	maxNameLen     = 64
+	// touchPeriod limits writes of last usage time to one per key in this period, storage keeps it
+	// with the same precision
+	touchPeriod = time.Minute
+)
+
+type IAPIKeyStorage interface {
+	AddAPIKey(ctx context.Context, name string, prefix string, keyHash []byte,
+		scopes []models.Scope) (*models.APIKey, error)
+	GetAPIKeyByHash(ctx context.Context, keyHash []byte) (*models.APIKey, error)
+	GetAPIKeysList(ctx context.Context) ([]*models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, apiKeyID uint64) error
+	TouchAPIKey(ctx context.Context, apiKeyID uint64) error
+}
+
+type APIKeyService struct {
+	storage IAPIKeyStorage
+	touches *debounce.Debouncer
+}
+
+func NewAPIKeyService(apiKeyStorage IAPIKeyStorage) (*APIKeyService, error) {
+	return &APIKeyService{storage: apiKeyStorage, touches: debounce.New(touchPeriod)}, nil
+}
+
+// CreateAPIKey generates new key with scopes. Key itself is returned only here, storage keeps its hash.
+func (a *APIKeyService) CreateAPIKey(ctx context.Context, name string, scopes []models.Scope,
+) (string, *models.APIKey, error) {
+	name = strings.TrimSpace(name)
+
+	if fieldErrors := validateAPIKey(name, scopes); len(fieldErrors) != 0 {
+		return "", nil, myerrors.NewValidationErrors(fieldErrors)
+	}
+
+	secret := make([]byte, keySecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	key := keyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	apiKey, err := a.storage.AddAPIKey(ctx, name, key[:shownPrefixLen], hashKey(key), scopes)
+	if err != nil {
+		return "", nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	return key, apiKey, nil
+}
+
+func (a *APIKeyService) GetAPIKeysList(ctx context.Context) ([]*models.APIKey, error) {
+	apiKeys, err := a.storage.GetAPIKeysList(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	return apiKeys, nil
+}
+
+func (a *APIKeyService) RevokeAPIKey(ctx context.Context, apiKeyID uint64) error {
+	if err := a.storage.RevokeAPIKey(ctx, apiKeyID); err != nil {
+		return fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	return nil
+}
+
+// Authenticate returns not revoked key presented by client or ErrInvalidAPIKey.
+func (a *APIKeyService) Authenticate(ctx context.Context, key string) (*models.APIKey, error) {
+	if !strings.HasPrefix(key, keyPrefix) {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, ErrInvalidAPIKey)
+	}
+
+	apiKey, err := a.storage.GetAPIKeyByHash(ctx, hashKey(key))
+	if err != nil {
+		if errors.Is(err, apikeyrepo.ErrAPIKeyNotFound) {
+			return nil, fmt.Errorf(myerrors.ErrTemplate, ErrInvalidAPIKey)
+		}
+
+		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	// request mustn't fail or wait because of statistics, so most requests don't write it at all
+	if a.touches.Allow(apiKey.ID) {
+		if err := a.storage.TouchAPIKey(ctx, apiKey.ID); err != nil {
+			my_logger.FromContext(ctx).Warnf("in Authenticate: %+v", err)
+		}
+	}
+
+	return apiKey, nil
+}
+
+func hashKey(key string) []byte {
+	hash := sha256.Sum256([]byte(key))
+
+	return hash[:]
+}
+
+func validateAPIKey(name string, scopes []models.Scope) []myerrors.FieldError {
+	var fieldErrors []myerrors.FieldError
+
+	if name == "" {
+		fieldErrors = append(fieldErrors, myerrors.NewFieldError("name", utils.CodeRequired, "name_empty"))
+	} else if utf8.RuneCountInString(name) > maxNameLen {
+		fieldErrors = append(fieldErrors, myerrors.NewFieldError("name", utils.CodeOutOfRange, "name_too_long"))
+	}
+
+	if len(scopes) == 0 {
+		fieldErrors = append(fieldErrors, myerrors.NewFieldError("scopes", utils.CodeRequired, "field_required"))
+	}
+
+	for _, scope := range scopes {
+		if !models.IsKnownScope(scope) {
+			fieldErrors = append(fieldErrors, myerrors.NewFieldError("scopes", utils.CodeInvalid, "scope_unknown", scope))
+		}
+	}
+
+	return fieldErrors
+}
@@ -9,7 +9,6 @@ import (
 	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
 	"github.com/SanExpett/auto-catalog/pkg/my_logger"
 	"github.com/SanExpett/auto-catalog/pkg/utils"
-	"go.uber.org/zap"
 	"io"
 	"net/http"
 )
@@ -27,18 +26,11 @@ type ICarService interface {
 
 type CarHandler struct {
 	service ICarService
-	logger  *zap.SugaredLogger
 }
 
 func NewCarHandler(CarService ICarService) (*CarHandler, error) {
-	logger, err := my_logger.Get()
-	if err != nil {
-		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
-	}
-
 	return &CarHandler{
 		service: CarService,
-		logger:  logger,
 	}, nil
 }
 
@@ -58,25 +50,24 @@ func NewCarHandler(CarService ICarService) (*CarHandler, error) {
 //	@Failure    405  {string} string
 //	@Failure    500  {string} string
 //	@Failure    222  {object} delivery.ErrorResponse "Error"
-//	@Router      /car/add [post]
+//	@Failure    401  {object} delivery.ErrorResponse "Unauthorized"
+//	@Failure    403  {object} delivery.ErrorResponse "Forbidden"
+//	@Failure    429  {object} delivery.ErrorResponse "Too Many Requests"
+//	@Failure    503  {object} delivery.ErrorResponse "Service Unavailable"
+//	@Router      /v1/car/add [post]
 func (p *CarHandler) AddCarHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, `Method not allowed`, http.StatusMethodNotAllowed)
-
-		return
-	}
-
 	ctx := r.Context()
+	logger := my_logger.FromContext(ctx)
 
 	person, err := p.service.AddCar(ctx, r.Body)
 	if err != nil {
-		delivery.HandleErr(w, p.logger, err)
+		delivery.HandleErr(w, r, logger, err)
 
 		return
 	}
 
-	delivery.SendOkResponse(w, p.logger, NewCarResponse(delivery.StatusResponseSuccessful, person))
-	p.logger.Infof("in AddCarHandler: add Car: %+v", person)
+	delivery.SendOkResponse(w, r, logger, NewCarResponse(delivery.StatusResponseSuccessful, person))
+	logger.Infof("in AddCarHandler: add Car: %+v", person)
 }
 
 // GetCarHandler godoc
@@ -87,36 +78,38 @@ func (p *CarHandler) AddCarHandler(w http.ResponseWriter, r *http.Request) {
 //	@Accept      json
 //	@Produce    json
 //	@Param      id  query uint64 true  "Car id"
+//	@Param      If-None-Match  header string false  "ETag of cached body, 304 is sent while it is current"
 //	@Success    200  {object} CarResponse
+//	@Success    304  "Not Modified"
+//	@Header     200,304  {string} ETag "strong validator of body"
 //	@Failure    405  {string} string
 //	@Failure    500  {string} string
 //	@Failure    222  {object} delivery.ErrorResponse "Error"
-//	@Router      /car/get [get]
+//	@Failure    401  {object} delivery.ErrorResponse "Unauthorized"
+//	@Failure    403  {object} delivery.ErrorResponse "Forbidden"
+//	@Failure    429  {object} delivery.ErrorResponse "Too Many Requests"
+//	@Failure    503  {object} delivery.ErrorResponse "Service Unavailable"
+//	@Router      /v1/car/get [get]
 func (p *CarHandler) GetCarHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, `Method not allowed`, http.StatusMethodNotAllowed)
-
-		return
-	}
-
 	ctx := r.Context()
+	logger := my_logger.FromContext(ctx)
 
 	CarID, err := utils.ParseUint64FromRequest(r, "id")
 	if err != nil {
-		delivery.HandleErr(w, p.logger, err)
+		delivery.HandleErr(w, r, logger, err)
 
 		return
 	}
 
 	Car, err := p.service.GetCar(ctx, CarID)
 	if err != nil {
-		delivery.HandleErr(w, p.logger, err)
+		delivery.HandleErr(w, r, logger, err)
 
 		return
 	}
 
-	delivery.SendOkResponse(w, p.logger, NewCarResponse(delivery.StatusResponseSuccessful, Car))
-	p.logger.Infof("in GetCarHandler: get Car: %+v", Car)
+	delivery.SendOkResponse(w, r, logger, NewCarResponse(delivery.StatusResponseSuccessful, Car))
+	logger.Infof("in GetCarHandler: get Car: %+v", Car)
 }
 
 // DeleteCarHandler godoc
@@ -132,33 +125,33 @@ func (p *CarHandler) GetCarHandler(w http.ResponseWriter, r *http.Request) {
 //	@Failure    405  {string} string
 //	@Failure    500  {string} string
 //	@Failure    222  {object} delivery.ErrorResponse "Error"
-//	@Router      /car/delete [delete]
+//	@Failure    401  {object} delivery.ErrorResponse "Unauthorized"
+//	@Failure    403  {object} delivery.ErrorResponse "Forbidden"
+//	@Failure    429  {object} delivery.ErrorResponse "Too Many Requests"
+//	@Failure    503  {object} delivery.ErrorResponse "Service Unavailable"
+//	@Router      /v1/car/delete [delete]
 func (c *CarHandler) DeleteCarHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodDelete {
-		http.Error(w, `Method not allowed`, http.StatusMethodNotAllowed)
-
-		return
-	}
-
 	ctx := r.Context()
+	logger := my_logger.FromContext(ctx)
 
 	carID, err := utils.ParseUint64FromRequest(r, "id")
 	if err != nil {
-		delivery.HandleErr(w, c.logger, err)
+		delivery.HandleErr(w, r, logger, err)
 
 		return
 	}
 
 	err = c.service.DeleteCar(ctx, carID)
 	if err != nil {
-		delivery.HandleErr(w, c.logger, err)
+		delivery.HandleErr(w, r, logger, err)
 
 		return
 	}
 
-	delivery.SendOkResponse(w, c.logger,
-		delivery.NewResponse(delivery.StatusResponseSuccessful, ResponseSuccessfulDeleteCar))
-	c.logger.Infof("in DeleteCarHandler: delete Car id=%d", carID)
+	delivery.SendOkResponse(w, r, logger,
+		delivery.NewResponse(delivery.StatusResponseSuccessful,
+			myerrors.Message(delivery.LangFromContext(ctx), ResponseSuccessfulDeleteCar)))
+	logger.Infof("in DeleteCarHandler: delete Car id=%d", carID)
 }
 
 // UpdateCarHandler godoc
@@ -174,18 +167,18 @@ func (c *CarHandler) DeleteCarHandler(w http.ResponseWriter, r *http.Request) {
 //	@Failure    405  {string} string
 //	@Failure    500  {string} string
 //	@Failure    222  {object} delivery.ErrorResponse "Error"
-//	@Router      /car/update [patch]
-//	@Router      /car/update [put]
+//	@Failure    401  {object} delivery.ErrorResponse "Unauthorized"
+//	@Failure    403  {object} delivery.ErrorResponse "Forbidden"
+//	@Failure    429  {object} delivery.ErrorResponse "Too Many Requests"
+//	@Failure    503  {object} delivery.ErrorResponse "Service Unavailable"
+//	@Router      /v1/car/update [patch]
+//	@Router      /v1/car/update [put]
 func (c *CarHandler) UpdateCarHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPatch && r.Method != http.MethodPut {
-		http.Error(w, `Method not allowed`, http.StatusMethodNotAllowed)
-
-		return
-	}
+	logger := my_logger.FromContext(r.Context())
 
 	carID, err := utils.ParseUint64FromRequest(r, "id")
 	if err != nil {
-		delivery.HandleErr(w, c.logger, err)
+		delivery.HandleErr(w, r, logger, err)
 
 		return
 	}
@@ -199,13 +192,13 @@ func (c *CarHandler) UpdateCarHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err != nil {
-		delivery.HandleErr(w, c.logger, err)
+		delivery.HandleErr(w, r, logger, err)
 
 		return
 	}
 
-	delivery.SendOkResponse(w, c.logger, delivery.NewResponseID(carID))
-	c.logger.Infof("in UpdateCarHandler: updated Car with id = %+v", carID)
+	delivery.SendOkResponse(w, r, logger, delivery.NewResponseID(carID))
+	logger.Infof("in UpdateCarHandler: updated Car with id = %+v", carID)
 }
 
 // GetCarsListHandler godoc
@@ -215,25 +208,41 @@ func (c *CarHandler) UpdateCarHandler(w http.ResponseWriter, r *http.Request) {
 //	@Tags Car
 //	@Accept      json
 //	@Produce    json
-//	@Param      limit  query uint64 true  "limit Cars"
-//	@Param      offset  query uint64 true  "offset of Cars"
-//	@Param      mark  query string true  "mark of cars in list"
-//	@Param      model  query string true  "model of cars in list"
-//	@Param      sort_by_year_type query uint64 true  "type of sort(0 - by year desc, 1 - by year asc)"
+//	@Param      limit  query uint64 false  "limit Cars, 10 by default"
+//	@Param      offset  query uint64 false  "offset of Cars"
+//	@Param      mark  query string false  "mark of cars in list"
+//	@Param      model  query string false  "model of cars in list"
+//	@Param      owner_id  query uint64 false  "id of owner of cars in list"
+//	@Param      sort_by_year_type query uint64 false  "type of sort(0 - by year desc, 1 - by year asc)"
+//	@Param      If-None-Match  header string false  "ETag of cached body, 304 is sent while it is current"
 //	@Success    200  {object} CarListResponse
+//	@Success    304  "Not Modified"
+//	@Header     200,304  {string} ETag "strong validator of body"
 //	@Failure    405  {string} string
 //	@Failure    500  {string} string
 //	@Failure    222  {object} delivery.ErrorResponse "Error"
-//	@Router      /car/get_list [get]
+//	@Failure    401  {object} delivery.ErrorResponse "Unauthorized"
+//	@Failure    403  {object} delivery.ErrorResponse "Forbidden"
+//	@Failure    429  {object} delivery.ErrorResponse "Too Many Requests"
+//	@Failure    503  {object} delivery.ErrorResponse "Service Unavailable"
+//	@Router      /v1/car/get_list [get]
 func (c *CarHandler) GetCarsListHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, `Method not allowed`, http.StatusMethodNotAllowed)
+	ctx := r.Context()
+	logger := my_logger.FromContext(ctx)
+
+	cars, err := c.getCarsList(r)
+	if err != nil {
+		delivery.HandleErr(w, r, logger, err)
 
 		return
 	}
 
-	ctx := r.Context()
+	delivery.SendOkResponse(w, r, logger, NewCarListResponse(delivery.StatusResponseSuccessful, cars))
+	logger.Infof("in GetCarListHandler: get Car list: %+v", cars)
+}
 
+// getCarsList returns cars chosen by query and path parameters of r.
+func (c *CarHandler) getCarsList(r *http.Request) ([]*models.Car, error) {
 	limit, err := utils.ParseUint64FromRequest(r, "limit")
 	if err != nil {
 		limit = 10
@@ -257,13 +266,261 @@ func (c *CarHandler) GetCarsListHandler(w http.ResponseWriter, r *http.Request)
 	model := utils.ParseStringFromRequest(r, "model")
 	mark := utils.ParseStringFromRequest(r, "mark")
 
-	cars, err := c.service.GetCarsList(ctx, limit, offset, model, mark, ownerID, sortByYearType)
+	cars, err := c.service.GetCarsList(r.Context(), limit, offset, model, mark, ownerID, sortByYearType)
+	if err != nil {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	return cars, nil
+}
+
+// GetCarsListV2Handler godoc
+//
+//	@Summary    get Cars list
+//	@Description  get Cars by limit and offset, filtered by mark, model and owner
+//	@Tags Car
+//	@Produce    json,application/problem+json
+//	@Param      limit  query uint64 false  "limit Cars, 10 by default"
+//	@Param      offset  query uint64 false  "offset of Cars"
+//	@Param      mark  query string false  "mark of cars in list"
+//	@Param      model  query string false  "model of cars in list"
+//	@Param      owner_id  query uint64 false  "id of owner of cars in list"
+//	@Param      sort_by_year_type query uint64 false  "type of sort(0 - by year desc, 1 - by year asc)"
+//	@Param      If-None-Match  header string false  "ETag of cached body, 304 is sent while it is current"
+//	@Success    200  {array} models.Car
+//	@Success    304  "Not Modified"
+//	@Header     200,304  {string} ETag "strong validator of body"
+//	@Failure    401  {object} delivery.Problem "Unauthorized"
+//	@Failure    403  {object} delivery.Problem "Forbidden"
+//	@Failure    405  {object} delivery.Problem "Method Not Allowed"
+//	@Failure    429  {object} delivery.Problem "Too Many Requests"
+//	@Failure    500  {object} delivery.Problem "Internal Server Error"
+//	@Failure    503  {object} delivery.Problem "Service Unavailable"
+//	@Router      /v2/cars [get]
+func (c *CarHandler) GetCarsListV2Handler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := my_logger.FromContext(ctx)
+
+	cars, err := c.getCarsList(r)
+	if err != nil {
+		delivery.HandleErr(w, r, logger, err)
+
+		return
+	}
+
+	if cars == nil {
+		cars = []*models.Car{}
+	}
+
+	delivery.SendOkResponse(w, r, logger, cars)
+	logger.Infof("in GetCarsListV2Handler: get Car list: %+v", cars)
+}
+
+// GetCarsOfOwnerV2Handler godoc
+//
+//	@Summary    get Cars of owner
+//	@Description  get Cars of person by limit and offset, filtered by mark and model
+//	@Tags Car
+//	@Produce    json,application/problem+json
+//	@Param      owner_id  path uint64 true  "id of owner of cars in list"
+//	@Param      limit  query uint64 false  "limit Cars, 10 by default"
+//	@Param      offset  query uint64 false  "offset of Cars"
+//	@Param      mark  query string false  "mark of cars in list"
+//	@Param      model  query string false  "model of cars in list"
+//	@Param      sort_by_year_type query uint64 false  "type of sort(0 - by year desc, 1 - by year asc)"
+//	@Param      If-None-Match  header string false  "ETag of cached body, 304 is sent while it is current"
+//	@Success    200  {array} models.Car
+//	@Success    304  "Not Modified"
+//	@Header     200,304  {string} ETag "strong validator of body"
+//	@Failure    401  {object} delivery.Problem "Unauthorized"
+//	@Failure    403  {object} delivery.Problem "Forbidden"
+//	@Failure    405  {object} delivery.Problem "Method Not Allowed"
+//	@Failure    429  {object} delivery.Problem "Too Many Requests"
+//	@Failure    500  {object} delivery.Problem "Internal Server Error"
+//	@Failure    503  {object} delivery.Problem "Service Unavailable"
+//	@Router      /v2/people/{owner_id}/cars [get]
+func (c *CarHandler) GetCarsOfOwnerV2Handler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := my_logger.FromContext(ctx)
+
+	cars, err := c.getCarsList(r)
+	if err != nil {
+		delivery.HandleErr(w, r, logger, err)
+
+		return
+	}
+
+	if cars == nil {
+		cars = []*models.Car{}
+	}
+
+	delivery.SendOkResponse(w, r, logger, cars)
+	logger.Infof("in GetCarsOfOwnerV2Handler: get Car list: %+v", cars)
+}
+
+// AddCarV2Handler godoc
+//
+//	@Summary    add Car
+//	@Description  add Car by data
+//	@Tags Car
+//	@Accept      json
+//	@Produce    json,application/problem+json
+//	@Param      Car  body models.PreCar true  "Car data for adding"
+//	@Success    201  {object} models.Car
+//	@Header     201  {string} Location "path of added Car"
+//	@Failure    400  {object} delivery.Problem "Bad Request"
+//	@Failure    401  {object} delivery.Problem "Unauthorized"
+//	@Failure    403  {object} delivery.Problem "Forbidden"
+//	@Failure    405  {object} delivery.Problem "Method Not Allowed"
+//	@Failure    409  {object} delivery.Problem "Conflict"
+//	@Failure    422  {object} delivery.Problem "Unprocessable Entity"
+//	@Failure    429  {object} delivery.Problem "Too Many Requests"
+//	@Failure    500  {object} delivery.Problem "Internal Server Error"
+//	@Failure    503  {object} delivery.Problem "Service Unavailable"
+//	@Router      /v2/cars [post]
+func (c *CarHandler) AddCarV2Handler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := my_logger.FromContext(ctx)
+
+	car, err := c.service.AddCar(ctx, r.Body)
+	if err != nil {
+		delivery.HandleErr(w, r, logger, err)
+
+		return
+	}
+
+	delivery.SendCreatedResponse(w, r, logger, fmt.Sprintf("/api/v2/cars/%d", car.ID), car)
+	logger.Infof("in AddCarV2Handler: add Car: %+v", car)
+}
+
+// GetCarV2Handler godoc
+//
+//	@Summary    get Car
+//	@Description  get Car by id
+//	@Tags Car
+//	@Produce    json,application/problem+json
+//	@Param      id  path uint64 true  "Car id"
+//	@Param      If-None-Match  header string false  "ETag of cached body, 304 is sent while it is current"
+//	@Success    200  {object} models.Car
+//	@Success    304  "Not Modified"
+//	@Header     200,304  {string} ETag "strong validator of body"
+//	@Failure    400  {object} delivery.Problem "Bad Request"
+//	@Failure    401  {object} delivery.Problem "Unauthorized"
+//	@Failure    403  {object} delivery.Problem "Forbidden"
+//	@Failure    404  {object} delivery.Problem "Not Found"
+//	@Failure    405  {object} delivery.Problem "Method Not Allowed"
+//	@Failure    429  {object} delivery.Problem "Too Many Requests"
+//	@Failure    500  {object} delivery.Problem "Internal Server Error"
+//	@Failure    503  {object} delivery.Problem "Service Unavailable"
+//	@Router      /v2/cars/{id} [get]
+func (c *CarHandler) GetCarV2Handler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := my_logger.FromContext(ctx)
+
+	carID, err := utils.ParseUint64FromRequest(r, "id")
+	if err != nil {
+		delivery.HandleErr(w, r, logger, err)
+
+		return
+	}
+
+	car, err := c.service.GetCar(ctx, carID)
+	if err != nil {
+		delivery.HandleErr(w, r, logger, err)
+
+		return
+	}
+
+	delivery.SendOkResponse(w, r, logger, car)
+	logger.Infof("in GetCarV2Handler: get Car: %+v", car)
+}
+
+// UpdateCarV2Handler godoc
+//
+//	@Summary    update Car
+//	@Description  update Car by id, PATCH changes only given fields
+//	@Tags Car
+//	@Accept      json
+//	@Produce    json,application/problem+json
+//	@Param      id path uint64 true  "Car id"
+//	@Param      preCar  body models.PreCar true  "Car data, fields are optional for PATCH"
+//	@Success    200  {object} models.Car
+//	@Failure    400  {object} delivery.Problem "Bad Request"
+//	@Failure    401  {object} delivery.Problem "Unauthorized"
+//	@Failure    403  {object} delivery.Problem "Forbidden"
+//	@Failure    404  {object} delivery.Problem "Not Found"
+//	@Failure    405  {object} delivery.Problem "Method Not Allowed"
+//	@Failure    409  {object} delivery.Problem "Conflict"
+//	@Failure    422  {object} delivery.Problem "Unprocessable Entity"
+//	@Failure    429  {object} delivery.Problem "Too Many Requests"
+//	@Failure    500  {object} delivery.Problem "Internal Server Error"
+//	@Failure    503  {object} delivery.Problem "Service Unavailable"
+//	@Router      /v2/cars/{id} [patch]
+//	@Router      /v2/cars/{id} [put]
+func (c *CarHandler) UpdateCarV2Handler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := my_logger.FromContext(ctx)
+
+	carID, err := utils.ParseUint64FromRequest(r, "id")
+	if err != nil {
+		delivery.HandleErr(w, r, logger, err)
+
+		return
+	}
+
+	err = c.service.UpdateCar(ctx, r.Body, r.Method == http.MethodPatch, carID)
+	if err != nil {
+		delivery.HandleErr(w, r, logger, err)
+
+		return
+	}
+
+	car, err := c.service.GetCar(ctx, carID)
+	if err != nil {
+		delivery.HandleErr(w, r, logger, err)
+
+		return
+	}
+
+	delivery.SendOkResponse(w, r, logger, car)
+	logger.Infof("in UpdateCarV2Handler: updated Car: %+v", car)
+}
+
+// DeleteCarV2Handler godoc
+//
+//	@Summary     delete Car
+//	@Description  delete Car by id. Recovery will be impossible
+//	@Tags Car
+//	@Produce    json,application/problem+json
+//	@Param      id  path uint64 true  "Car id"
+//	@Success    204  "No Content"
+//	@Failure    400  {object} delivery.Problem "Bad Request"
+//	@Failure    401  {object} delivery.Problem "Unauthorized"
+//	@Failure    403  {object} delivery.Problem "Forbidden"
+//	@Failure    404  {object} delivery.Problem "Not Found"
+//	@Failure    405  {object} delivery.Problem "Method Not Allowed"
+//	@Failure    429  {object} delivery.Problem "Too Many Requests"
+//	@Failure    500  {object} delivery.Problem "Internal Server Error"
+//	@Failure    503  {object} delivery.Problem "Service Unavailable"
+//	@Router      /v2/cars/{id} [delete]
+func (c *CarHandler) DeleteCarV2Handler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := my_logger.FromContext(ctx)
+
+	carID, err := utils.ParseUint64FromRequest(r, "id")
+	if err != nil {
+		delivery.HandleErr(w, r, logger, err)
+
+		return
+	}
+
+	err = c.service.DeleteCar(ctx, carID)
 	if err != nil {
-		delivery.HandleErr(w, c.logger, err)
+		delivery.HandleErr(w, r, logger, err)
 
 		return
 	}
 
-	delivery.SendOkResponse(w, c.logger, NewCarListResponse(delivery.StatusResponseSuccessful, cars))
-	c.logger.Infof("in GetCarListHandler: get Car list: %+v", cars)
+	delivery.SendNoContent(w)
+	logger.Infof("in DeleteCarV2Handler: delete Car id=%d", carID)
 }
@@ -3,7 +3,7 @@ package delivery
 import "github.com/SanExpett/auto-catalog/pkg/models"
 
 const (
-	ResponseSuccessfulDeleteCar = "Автомобиль успешно удален"
+	ResponseSuccessfulDeleteCar = "car_deleted"
 )
 
 type CarResponse struct {
@@ -24,6 +24,10 @@ type CarListResponse struct {
 }
 
 func NewCarListResponse(status int, body []*models.Car) *CarListResponse {
+	if body == nil {
+		body = []*models.Car{}
+	}
+
 	return &CarListResponse{
 		Status: status,
 		Body:   body,
@@ -6,23 +6,35 @@ import (
 	"fmt"
 	"github.com/Masterminds/squirrel"
 	"github.com/SanExpett/auto-catalog/internal/server/repository"
+	"github.com/SanExpett/auto-catalog/pkg/metrics"
 	"github.com/SanExpett/auto-catalog/pkg/models"
 	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
 	"github.com/SanExpett/auto-catalog/pkg/my_logger"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
-	"go.uber.org/zap"
-	"strconv"
 	"time"
 )
 
 var (
-	ErrCarNotFound       = myerrors.NewError("Эта машина не найдена")
-	ErrNoAffectedCarRows = myerrors.NewError("Не получилось обновить данные автомобиля")
-	ErrNoUpdateFields    = myerrors.NewError("Вы пытаетесь обновить пустое количество полей автомобиля")
-	ErrNoPerson          = myerrors.NewError("Вы пытаетесь добавить машину для несуществующего человека")
+	ErrCarNotFound       = myerrors.NewNotFoundError("car_not_found")
+	ErrNoAffectedCarRows = myerrors.NewNotFoundError("car_not_updated")
+	ErrNoUpdateFields    = myerrors.NewError("car_no_update_fields")
+	ErrNoPerson          = myerrors.NewValidationError("car_owner_not_found")
 
 	NameSeqCar = pgx.Identifier{"public", "car_id_seq"} //nolint:gochecknoglobals
+
+	// CarConstraints are constraints of table car, memory storage checks them too
+	CarConstraints = map[string]repository.Constraint{ //nolint:gochecknoglobals
+		"car_reg_num_key":   {Field: "reg_num", Key: "reg_num_already_registered"},
+		"car_reg_num_check": {Field: "reg_num", Key: "reg_num_empty"},
+		"len_reg_num":       {Field: "reg_num", Key: "reg_num_length"},
+		"car_mark_check":    {Field: "mark", Key: "mark_empty"},
+		"max_len_mark":      {Field: "mark", Key: "mark_too_long"},
+		"car_model_check":   {Field: "model", Key: "model_empty"},
+		"max_len_model":     {Field: "model", Key: "model_too_long"},
+		"correct_year":      {Field: "year", Key: "year_out_of_range"},
+		"car_owner_id_fkey": {Field: "owner_id", Key: "owner_not_exists"},
+	}
 )
 
 const (
@@ -31,20 +43,11 @@ const (
 )
 
 type CarStorage struct {
-	pool   *pgxpool.Pool
-	logger *zap.SugaredLogger
+	pool *pgxpool.Pool
 }
 
 func NewCarStorage(pool *pgxpool.Pool) (*CarStorage, error) {
-	logger, err := my_logger.Get()
-	if err != nil {
-		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
-	}
-
-	return &CarStorage{
-		pool:   pool,
-		logger: logger,
-	}, nil
+	return &CarStorage{pool: pool}, nil
 }
 
 func (p *CarStorage) selectCreatedAtByCarID(ctx context.Context, tx pgx.Tx, carID uint64,
@@ -59,7 +62,7 @@ func (p *CarStorage) selectCreatedAtByCarID(ctx context.Context, tx pgx.Tx, carI
 			return time.Time{}, fmt.Errorf(myerrors.ErrTemplate, ErrCarNotFound)
 		}
 
-		p.logger.Errorf("error with CarId=%d: %+v", carID, err)
+		my_logger.FromContext(ctx).Errorf("error with CarId=%d: %+v", carID, err)
 
 		return time.Time{}, fmt.Errorf(myerrors.ErrTemplate, err)
 	}
@@ -78,7 +81,7 @@ func (p *CarStorage) checkPersonExistByID(ctx context.Context, tx pgx.Tx, person
 			return false, fmt.Errorf(myerrors.ErrTemplate, ErrCarNotFound)
 		}
 
-		p.logger.Errorf("error with personID=%d: %+v", personID, err)
+		my_logger.FromContext(ctx).Errorf("error with personID=%d: %+v", personID, err)
 
 		return false, fmt.Errorf(myerrors.ErrTemplate, err)
 	}
@@ -91,15 +94,17 @@ func (p *CarStorage) insertCar(ctx context.Context, tx pgx.Tx, preCar *models.Pr
 	_, err := tx.Exec(ctx, SQLInsertCar, preCar.OwnerID, preCar.RegNum, preCar.Mark, preCar.Model, preCar.Year)
 
 	if err != nil {
-		p.logger.Errorln(err)
+		my_logger.FromContext(ctx).Errorln(err)
 
-		return fmt.Errorf(myerrors.ErrTemplate, err)
+		return fmt.Errorf(myerrors.ErrTemplate, repository.MapPgError(err, CarConstraints))
 	}
 
 	return nil
 }
 
 func (p *CarStorage) AddCar(ctx context.Context, preCar *models.PreCar) (*models.Car, error) {
+	defer metrics.ObserveQuery("car", "AddCar", time.Now())
+
 	car := &models.Car{OwnerID: preCar.OwnerID, RegNum: preCar.RegNum, Mark: preCar.Mark,
 		Model: preCar.Model, Year: preCar.Year}
 
@@ -134,7 +139,7 @@ func (p *CarStorage) AddCar(ctx context.Context, preCar *models.PreCar) (*models
 		return err
 	})
 	if err != nil {
-		p.logger.Errorln(err)
+		my_logger.FromContext(ctx).Errorln(err)
 
 		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
 	}
@@ -153,7 +158,7 @@ func (p *CarStorage) selectCarByID(ctx context.Context, tx pgx.Tx, carID uint64,
 			return nil, fmt.Errorf(myerrors.ErrTemplate, ErrCarNotFound)
 		}
 
-		p.logger.Errorf("error with CarId=%d: %+v", carID, err)
+		my_logger.FromContext(ctx).Errorf("error with CarId=%d: %+v", carID, err)
 
 		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
 	}
@@ -162,6 +167,8 @@ func (p *CarStorage) selectCarByID(ctx context.Context, tx pgx.Tx, carID uint64,
 }
 
 func (p *CarStorage) GetCar(ctx context.Context, CarID uint64) (*models.Car, error) {
+	defer metrics.ObserveQuery("car", "GetCar", time.Now())
+
 	var car *models.Car
 
 	err := pgx.BeginFunc(ctx, p.pool, func(tx pgx.Tx) error {
@@ -186,7 +193,7 @@ func (c *CarStorage) deleteCar(ctx context.Context, tx pgx.Tx, carID uint64) err
 
 	result, err := tx.Exec(ctx, SQLDeleteCar, carID)
 	if err != nil {
-		c.logger.Errorln(err)
+		my_logger.FromContext(ctx).Errorln(err)
 
 		return fmt.Errorf(myerrors.ErrTemplate, err)
 	}
@@ -200,6 +207,8 @@ func (c *CarStorage) deleteCar(ctx context.Context, tx pgx.Tx, carID uint64) err
 }
 
 func (c *CarStorage) DeleteCar(ctx context.Context, carID uint64) error {
+	defer metrics.ObserveQuery("car", "DeleteCar", time.Now())
+
 	err := pgx.BeginFunc(ctx, c.pool, func(tx pgx.Tx) error {
 		err := c.deleteCar(ctx, tx, carID)
 		if err != nil {
@@ -209,7 +218,7 @@ func (c *CarStorage) DeleteCar(ctx context.Context, carID uint64) error {
 		return nil
 	})
 	if err != nil {
-		c.logger.Errorln(err)
+		my_logger.FromContext(ctx).Errorln(err)
 
 		return fmt.Errorf(myerrors.ErrTemplate, err)
 	}
@@ -229,16 +238,16 @@ func (c *CarStorage) updateCar(ctx context.Context, tx pgx.Tx,
 
 	queryString, args, err := query.ToSql()
 	if err != nil {
-		c.logger.Errorln(err)
+		my_logger.FromContext(ctx).Errorln(err)
 
 		return fmt.Errorf(myerrors.ErrTemplate, err)
 	}
 
 	result, err := tx.Exec(ctx, queryString, args...)
 	if err != nil {
-		c.logger.Errorln(err)
+		my_logger.FromContext(ctx).Errorln(err)
 
-		return fmt.Errorf(myerrors.ErrTemplate, err)
+		return fmt.Errorf(myerrors.ErrTemplate, repository.MapPgError(err, CarConstraints))
 	}
 
 	rowsAffected := result.RowsAffected()
@@ -250,13 +259,15 @@ func (c *CarStorage) updateCar(ctx context.Context, tx pgx.Tx,
 }
 
 func (c *CarStorage) UpdateCar(ctx context.Context, carID uint64, updateFields map[string]interface{}) error {
+	defer metrics.ObserveQuery("car", "UpdateCar", time.Now())
+
 	err := pgx.BeginFunc(ctx, c.pool, func(tx pgx.Tx) error {
 		err := c.updateCar(ctx, tx, carID, updateFields)
 
 		return err
 	})
 	if err != nil {
-		c.logger.Errorln(err)
+		my_logger.FromContext(ctx).Errorln(err)
 
 		return fmt.Errorf(myerrors.ErrTemplate, err)
 	}
@@ -273,14 +284,14 @@ func (c *CarStorage) selectCarsWithWhereOrderLimitOffset(ctx context.Context, tx
 
 	SQLQuery, args, err := query.ToSql()
 	if err != nil {
-		c.logger.Errorln(err)
+		my_logger.FromContext(ctx).Errorln(err)
 
 		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
 	}
 
 	rowsCars, err := tx.Query(ctx, SQLQuery, args...)
 	if err != nil {
-		c.logger.Errorln(err)
+		my_logger.FromContext(ctx).Errorln(err)
 
 		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
 	}
@@ -306,7 +317,7 @@ func (c *CarStorage) selectCarsWithWhereOrderLimitOffset(ctx context.Context, tx
 		return nil
 	})
 	if err != nil {
-		c.logger.Errorln(err)
+		my_logger.FromContext(ctx).Errorln(err)
 
 		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
 	}
@@ -317,30 +328,30 @@ func (c *CarStorage) selectCarsWithWhereOrderLimitOffset(ctx context.Context, tx
 func (c *CarStorage) GetCarsList(ctx context.Context, limit uint64, offset uint64,
 	model string, mark string, ownerID uint64, sortByYearType uint64,
 ) ([]*models.Car, error) {
+	defer metrics.ObserveQuery("car", "GetCarsList", time.Now())
+
 	var slCar []*models.Car
 
-	var orderByClause []string
+	// cars of same year are ordered by id, so pages don't overlap
+	orderByClause := []string{"id"}
 
 	switch sortByYearType {
 	case byYearDESC:
-		orderByClause = []string{"year DESC"}
+		orderByClause = []string{"year DESC", "id"}
 	case byYearASC:
-		orderByClause = []string{"year ASC"}
+		orderByClause = []string{"year ASC", "id"}
 	}
 
-	whereClause := ""
+	// values are passed as arguments of query, they must never be concatenated into it
+	whereClause := squirrel.Eq{}
 	if mark != "" {
-		whereClause += "mark = " + mark + " AND "
+		whereClause["mark"] = mark
 	}
 	if model != "" {
-		whereClause += "model = " + model + " AND "
+		whereClause["model"] = model
 	}
 	if ownerID != 0 {
-		whereClause += "owner_id = " + strconv.FormatUint(ownerID, 10) + " AND "
-	}
-
-	if whereClause != "" {
-		whereClause = whereClause[:len(whereClause)-5]
+		whereClause["owner_id"] = ownerID
 	}
 
 	err := pgx.BeginFunc(ctx, c.pool, func(tx pgx.Tx) error {
@@ -354,7 +365,7 @@ func (c *CarStorage) GetCarsList(ctx context.Context, limit uint64, offset uint6
 		return nil
 	})
 	if err != nil {
-		c.logger.Errorln(err)
+		my_logger.FromContext(ctx).Errorln(err)
 
 		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
 	}
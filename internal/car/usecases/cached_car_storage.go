@@ -0,0 +1,81 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"github.com/SanExpett/auto-catalog/pkg/cache"
+	"github.com/SanExpett/auto-catalog/pkg/models"
+	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
+)
+
+var _ ICarStorage = (*CachedCarStorage)(nil)
+
+// CachedCarStorage reads cars by id through cache, writes invalidate cars they change. It is below CarService,
+// so visibility of cars for owners is checked for cached cars too and cache is shared by all callers.
+type CachedCarStorage struct {
+	storage ICarStorage
+	cars    *cache.LRU[uint64, models.Car]
+}
+
+func NewCachedCarStorage(storage ICarStorage, cars *cache.LRU[uint64, models.Car]) (*CachedCarStorage, error) {
+	return &CachedCarStorage{storage: storage, cars: cars}, nil
+}
+
+// AddCar invalidates nothing, new car is not cached yet.
+func (c *CachedCarStorage) AddCar(ctx context.Context, preCar *models.PreCar) (*models.Car, error) {
+	car, err := c.storage.AddCar(ctx, preCar)
+	if err != nil {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	return car, nil
+}
+
+// GetCar returns copy of cached car, callers may change it.
+func (c *CachedCarStorage) GetCar(ctx context.Context, carID uint64) (*models.Car, error) {
+	car, err := c.cars.GetOrLoad(ctx, carID, func(ctx context.Context) (models.Car, error) {
+		car, err := c.storage.GetCar(ctx, carID)
+		if err != nil {
+			return models.Car{}, err //nolint:exhaustruct
+		}
+
+		return *car, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	return &car, nil
+}
+
+func (c *CachedCarStorage) DeleteCar(ctx context.Context, carID uint64) error {
+	defer c.cars.Delete(carID)
+
+	if err := c.storage.DeleteCar(ctx, carID); err != nil {
+		return fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	return nil
+}
+
+func (c *CachedCarStorage) UpdateCar(ctx context.Context, carID uint64, updateFields map[string]interface{}) error {
+	defer c.cars.Delete(carID)
+
+	if err := c.storage.UpdateCar(ctx, carID, updateFields); err != nil {
+		return fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	return nil
+}
+
+// GetCarsList is not cached, lists depend on too many parameters.
+func (c *CachedCarStorage) GetCarsList(ctx context.Context, limit uint64, offset uint64, model string, mark string,
+	ownerID uint64, sortByYearType uint64,
+) ([]*models.Car, error) {
+	cars, err := c.storage.GetCarsList(ctx, limit, offset, model, mark, ownerID, sortByYearType)
+	if err != nil {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	return cars, nil
+}
@@ -4,16 +4,19 @@ import (
 	"context"
 	"fmt"
 	carrepo "github.com/SanExpett/auto-catalog/internal/car/repository"
+	"github.com/SanExpett/auto-catalog/pkg/access"
 	"github.com/SanExpett/auto-catalog/pkg/models"
 	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
-	"github.com/SanExpett/auto-catalog/pkg/my_logger"
 	"github.com/SanExpett/auto-catalog/pkg/utils"
-	"go.uber.org/zap"
 	"io"
+	"sort"
 )
 
 var _ ICarStorage = (*carrepo.CarStorage)(nil)
 
+// ownerUpdatableFields are fields of car which owner may change by partial update.
+var ownerUpdatableFields = map[string]bool{"mark": true, "model": true, "year": true} //nolint:gochecknoglobals
+
 type ICarStorage interface {
 	AddCar(ctx context.Context, preCar *models.PreCar) (*models.Car, error)
 	GetCar(ctx context.Context, CarID uint64) (*models.Car, error)
@@ -25,20 +28,18 @@ type ICarStorage interface {
 
 type CarService struct {
 	storage ICarStorage
-	logger  *zap.SugaredLogger
 }
 
 func NewCarService(CarStorage ICarStorage) (*CarService, error) {
-	logger, err := my_logger.Get()
-	if err != nil {
-		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
-	}
-
-	return &CarService{storage: CarStorage, logger: logger}, nil
+	return &CarService{storage: CarStorage}, nil
 }
 
 func (p *CarService) AddCar(ctx context.Context, r io.Reader) (*models.Car, error) {
-	preCar, err := ValidatePreCar(r)
+	if _, isOwner := access.OwnerFromContext(ctx); isOwner {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, access.ErrOwnerForbidden)
+	}
+
+	preCar, err := ValidatePreCar(ctx, r)
 	if err != nil {
 		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
 	}
@@ -52,17 +53,39 @@ func (p *CarService) AddCar(ctx context.Context, r io.Reader) (*models.Car, erro
 }
 
 func (p *CarService) GetCar(ctx context.Context, carID uint64) (*models.Car, error) {
+	car, err := p.getVisibleCar(ctx, carID)
+	if err != nil {
+		return nil, err
+	}
+
+	car.Sanitize()
+
+	return car, nil
+}
+
+// getVisibleCar returns car if caller may see it, cars of other people are not found for owner.
+func (p *CarService) getVisibleCar(ctx context.Context, carID uint64) (*models.Car, error) {
 	car, err := p.storage.GetCar(ctx, carID)
 	if err != nil {
 		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
 	}
 
-	car.Sanitize()
+	if personID, isOwner := access.OwnerFromContext(ctx); isOwner && car.OwnerID != personID {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, carrepo.ErrCarNotFound)
+	}
 
 	return car, nil
 }
 
 func (c *CarService) DeleteCar(ctx context.Context, carID uint64) error {
+	if _, isOwner := access.OwnerFromContext(ctx); isOwner {
+		if _, err := c.getVisibleCar(ctx, carID); err != nil {
+			return err
+		}
+
+		return fmt.Errorf(myerrors.ErrTemplate, access.ErrOwnerForbidden)
+	}
+
 	err := c.storage.DeleteCar(ctx, carID)
 	if err != nil {
 		return fmt.Errorf(myerrors.ErrTemplate, err)
@@ -77,12 +100,12 @@ func (c *CarService) UpdateCar(ctx context.Context, r io.Reader, isPartialUpdate
 	var err error
 
 	if isPartialUpdate {
-		preCar, err = ValidatePartOfPreCar(r)
+		preCar, err = ValidatePartOfPreCar(ctx, r)
 		if err != nil {
 			return fmt.Errorf(myerrors.ErrTemplate, err)
 		}
 	} else {
-		preCar, err = ValidatePreCar(r)
+		preCar, err = ValidatePreCar(ctx, r)
 		if err != nil {
 			return fmt.Errorf(myerrors.ErrTemplate, err)
 		}
@@ -90,6 +113,12 @@ func (c *CarService) UpdateCar(ctx context.Context, r io.Reader, isPartialUpdate
 
 	updateFieldsMap := utils.StructToMap(preCar)
 
+	if _, isOwner := access.OwnerFromContext(ctx); isOwner {
+		if err := c.checkOwnerUpdate(ctx, carID, isPartialUpdate, updateFieldsMap); err != nil {
+			return err
+		}
+	}
+
 	err = c.storage.UpdateCar(ctx, carID, updateFieldsMap)
 	if err != nil {
 		return fmt.Errorf(myerrors.ErrTemplate, err)
@@ -98,9 +127,46 @@ func (c *CarService) UpdateCar(ctx context.Context, r io.Reader, isPartialUpdate
 	return nil
 }
 
+// checkOwnerUpdate lets owner change only ownerUpdatableFields of own car by partial update.
+func (c *CarService) checkOwnerUpdate(ctx context.Context, carID uint64, isPartialUpdate bool,
+	updateFields map[string]interface{},
+) error {
+	if _, err := c.getVisibleCar(ctx, carID); err != nil {
+		return err
+	}
+
+	if !isPartialUpdate {
+		return fmt.Errorf(myerrors.ErrTemplate, access.ErrOwnerForbidden)
+	}
+
+	fields := make([]string, 0, len(updateFields))
+	for field := range updateFields {
+		fields = append(fields, field)
+	}
+
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		if !ownerUpdatableFields[field] {
+			return fmt.Errorf(myerrors.ErrTemplate, myerrors.NewForbiddenError("car_field_forbidden", field))
+		}
+	}
+
+	return nil
+}
+
+// GetCarsList returns to owner only own cars, list of other person or of caller without person is empty.
 func (c *CarService) GetCarsList(ctx context.Context, limit uint64, offset uint64, model string, mark string,
 	ownerID uint64, sortByYearType uint64,
 ) ([]*models.Car, error) {
+	if personID, isOwner := access.OwnerFromContext(ctx); isOwner {
+		if personID == 0 || ownerID != 0 && ownerID != personID {
+			return []*models.Car{}, nil
+		}
+
+		ownerID = personID
+	}
+
 	cars, err := c.storage.GetCarsList(ctx, limit, offset, model, mark, ownerID, sortByYearType)
 	if err != nil {
 		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
@@ -1,77 +1,58 @@
 package usecases
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/SanExpett/auto-catalog/pkg/models"
 	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
 	"github.com/SanExpett/auto-catalog/pkg/my_logger"
-	"github.com/asaskevich/govalidator"
+	"github.com/SanExpett/auto-catalog/pkg/utils"
 	"io"
 )
 
 var (
-	ErrDecodePreCar = myerrors.NewError("Некорректный json машины")
+	ErrDecodePreCar = myerrors.NewError("car_decode")
 )
 
-func validatePreCar(r io.Reader) (*models.PreCar, error) {
-	logger, err := my_logger.Get()
-	if err != nil {
-		return nil, err
-	}
-
+func decodePreCar(ctx context.Context, r io.Reader) (*models.PreCar, error) {
 	decoder := json.NewDecoder(r)
 
 	preCar := &models.PreCar{}
 	if err := decoder.Decode(preCar); err != nil {
-		logger.Errorln(err)
+		my_logger.FromContext(ctx).Errorln(err)
+
+		if fieldError, ok := utils.FieldErrorFromJSON(err); ok {
+			return nil, myerrors.NewValidationErrors([]myerrors.FieldError{fieldError})
+		}
 
 		return nil, fmt.Errorf(myerrors.ErrTemplate, ErrDecodePreCar)
 	}
 
 	preCar.Trim()
 
-	_, err = govalidator.ValidateStruct(preCar)
-	if err != nil {
-		logger.Errorln(err)
-
-		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
-	}
-
-	return preCar, nil
-}
-
-func ValidatePreCar(r io.Reader) (*models.PreCar, error) {
-	preCar, err := validatePreCar(r)
-	if err != nil {
-		return nil, myerrors.NewError(err.Error())
-	}
-
 	return preCar, nil
 }
 
-func ValidatePartOfPreCar(r io.Reader) (*models.PreCar, error) {
-	logger, err := my_logger.Get()
+func validatePreCar(ctx context.Context, r io.Reader, isPartial bool) (*models.PreCar, error) {
+	preCar, err := decodePreCar(ctx, r)
 	if err != nil {
 		return nil, err
 	}
 
-	preCar, err := validatePreCar(r)
-	if preCar == nil {
-		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	fieldErrors := utils.ValidateStruct(preCar, isPartial)
+	if len(fieldErrors) != 0 {
+		return nil, myerrors.NewValidationErrors(fieldErrors)
 	}
 
-	if err != nil {
-		validationErrors := govalidator.ErrorsByField(err)
-
-		for field, err := range validationErrors {
-			if err != "non zero value required" {
-				logger.Errorln(err)
+	return preCar, nil
+}
 
-				return nil, myerrors.NewError("%s error: %s", field, err)
-			}
-		}
-	}
+func ValidatePreCar(ctx context.Context, r io.Reader) (*models.PreCar, error) {
+	return validatePreCar(ctx, r, false)
+}
 
-	return preCar, nil
+// ValidatePartOfPreCar validates only presented fields of PreCar.
+func ValidatePartOfPreCar(ctx context.Context, r io.Reader) (*models.PreCar, error) {
+	return validatePreCar(ctx, r, true)
 }
@@ -0,0 +1,539 @@
+package conformance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	carrepo "github.com/SanExpett/auto-catalog/internal/car/repository"
+	peoplerepo "github.com/SanExpett/auto-catalog/internal/people/repository"
+	"github.com/SanExpett/auto-catalog/pkg/models"
+	"strings"
+	"sync"
+)
+
+const (
+	byYearDESC = 0
+	byYearASC  = 1
+	// unsorted is sort type of list without sort by year
+	unsorted = 2
+
+	concurrentAdds = 8
+)
+
+func Cases() []Case {
+	return []Case{
+		{Name: "add and get person", Run: addAndGetPerson},
+		{Name: "missing person", Run: missingPerson},
+		{Name: "person constraints", Run: personConstraints},
+		{Name: "add and get car", Run: addAndGetCar},
+		{Name: "add car of missing person", Run: addCarOfMissingPerson},
+		{Name: "car constraints", Run: carConstraints},
+		{Name: "unique reg_num", Run: uniqueRegNum},
+		{Name: "unique reg_num under concurrent adds", Run: concurrentUniqueRegNum},
+		{Name: "missing car", Run: missingCar},
+		{Name: "update car", Run: updateCar},
+		{Name: "invalid update keeps car", Run: invalidUpdateKeepsCar},
+		{Name: "delete car", Run: deleteCar},
+		{Name: "delete person cascades to cars", Run: deletePersonCascades},
+		{Name: "filter cars", Run: filterCars},
+		{Name: "sort and paginate cars", Run: sortAndPaginateCars},
+		{Name: "filter values are not sql", Run: filterValuesAreNotSQL},
+	}
+}
+
+func addAndGetPerson(ctx context.Context, t *T) error {
+	added, err := t.AddPerson(ctx)
+	if err != nil {
+		return err
+	}
+
+	if added.ID == 0 || added.CreatedAt.IsZero() {
+		return fmt.Errorf("%w: added person has no id or created_at: %+v", ErrMismatch, added)
+	}
+
+	person, err := t.Storage.GetPerson(ctx, added.ID)
+	if err != nil {
+		return fmt.Errorf("get person: %w", err)
+	}
+
+	return errors.Join(
+		expectEqual("name", person.Name, added.Name),
+		expectEqual("surname", person.Surname, added.Surname),
+		expectEqual("patronymic", person.Patronymic, added.Patronymic),
+		expectEqual("created_at", person.CreatedAt.Unix(), added.CreatedAt.Unix()),
+	)
+}
+
+func missingPerson(ctx context.Context, t *T) error {
+	_, err := t.Storage.GetPerson(ctx, missingID)
+
+	return errors.Join(
+		expectError(err, peoplerepo.ErrPeopleNotFound),
+		expectError(t.Storage.DeletePerson(ctx, missingID), peoplerepo.ErrNoAffectedPeopleRows),
+	)
+}
+
+func personConstraints(ctx context.Context, t *T) error {
+	long := strings.Repeat("я", 65)
+
+	cases := []struct {
+		prePeople  models.PrePeople
+		constraint string
+	}{
+		{prePeople: models.PrePeople{Name: "", Surname: "Ivanov", Patronymic: ""}, constraint: "people_name_check"},
+		{prePeople: models.PrePeople{Name: "Ivan", Surname: "", Patronymic: ""}, constraint: "people_surname_check"},
+		{prePeople: models.PrePeople{Name: long, Surname: "Ivanov", Patronymic: ""}, constraint: "max_len_name"},
+		{prePeople: models.PrePeople{Name: "Ivan", Surname: long, Patronymic: ""}, constraint: "max_len_surname"},
+		{prePeople: models.PrePeople{Name: "Ivan", Surname: "Ivanov", Patronymic: long}, constraint: "max_len_patronymic"},
+	}
+
+	var errs []error
+
+	for _, c := range cases {
+		person, err := t.Storage.AddPerson(ctx, &c.prePeople)
+		if err == nil {
+			t.personIDs = append(t.personIDs, person.ID)
+		}
+
+		errs = append(errs, expectConstraint(err, c.constraint))
+	}
+
+	return errors.Join(errs...)
+}
+
+func addAndGetCar(ctx context.Context, t *T) error {
+	person, err := t.AddPerson(ctx)
+	if err != nil {
+		return err
+	}
+
+	added, err := t.AddCar(ctx, person.ID, "Lada", "Vesta", 2002)
+	if err != nil {
+		return err
+	}
+
+	if added.ID == 0 || added.CreatedAt.IsZero() {
+		return fmt.Errorf("%w: added car has no id or created_at: %+v", ErrMismatch, added)
+	}
+
+	car, err := t.Storage.GetCar(ctx, added.ID)
+	if err != nil {
+		return fmt.Errorf("get car: %w", err)
+	}
+
+	return errors.Join(
+		expectEqual("owner_id", car.OwnerID, person.ID),
+		expectEqual("reg_num", car.RegNum, added.RegNum),
+		expectEqual("mark", car.Mark, "Lada"),
+		expectEqual("model", car.Model, "Vesta"),
+		expectEqual("year", car.Year, 2002),
+		expectEqual("created_at", car.CreatedAt.Unix(), added.CreatedAt.Unix()),
+	)
+}
+
+func addCarOfMissingPerson(ctx context.Context, t *T) error {
+	_, err := t.Storage.AddCar(ctx, &models.PreCar{OwnerID: missingID, RegNum: t.RegNum(), Mark: "Lada",
+		Model: "Vesta", Year: 2002})
+
+	return expectError(err, carrepo.ErrNoPerson)
+}
+
+func carConstraints(ctx context.Context, t *T) error {
+	person, err := t.AddPerson(ctx)
+	if err != nil {
+		return err
+	}
+
+	long := strings.Repeat("я", 257)
+
+	cases := []struct {
+		preCar     models.PreCar
+		constraint string
+	}{
+		{preCar: models.PreCar{RegNum: "", Mark: "Lada", Model: "Vesta", Year: 2002}, constraint: "car_reg_num_check"},
+		{preCar: models.PreCar{RegNum: "X123XX15", Mark: "Lada", Model: "Vesta", Year: 2002}, constraint: "len_reg_num"},
+		{preCar: models.PreCar{RegNum: t.RegNum(), Mark: "", Model: "Vesta", Year: 2002}, constraint: "car_mark_check"},
+		{preCar: models.PreCar{RegNum: t.RegNum(), Mark: long, Model: "Vesta", Year: 2002}, constraint: "max_len_mark"},
+		{preCar: models.PreCar{RegNum: t.RegNum(), Mark: "Lada", Model: "", Year: 2002}, constraint: "car_model_check"},
+		{preCar: models.PreCar{RegNum: t.RegNum(), Mark: "Lada", Model: long, Year: 2002}, constraint: "max_len_model"},
+		{preCar: models.PreCar{RegNum: t.RegNum(), Mark: "Lada", Model: "Vesta", Year: 1884}, constraint: "correct_year"},
+		// checks are made in order of names of constraints
+		{preCar: models.PreCar{RegNum: "X", Mark: "", Model: "Vesta", Year: 0}, constraint: "car_mark_check"},
+	}
+
+	var errs []error
+
+	for _, c := range cases {
+		c.preCar.OwnerID = person.ID
+
+		_, err := t.Storage.AddCar(ctx, &c.preCar)
+		errs = append(errs, expectConstraint(err, c.constraint))
+	}
+
+	return errors.Join(errs...)
+}
+
+func uniqueRegNum(ctx context.Context, t *T) error {
+	person, err := t.AddPerson(ctx)
+	if err != nil {
+		return err
+	}
+
+	car, err := t.AddCar(ctx, person.ID, "Lada", "Vesta", 2002)
+	if err != nil {
+		return err
+	}
+
+	_, err = t.Storage.AddCar(ctx, &models.PreCar{OwnerID: person.ID, RegNum: car.RegNum, Mark: "Lada",
+		Model: "Granta", Year: 2015})
+
+	return expectConstraint(err, "car_reg_num_key")
+}
+
+func concurrentUniqueRegNum(ctx context.Context, t *T) error {
+	person, err := t.AddPerson(ctx)
+	if err != nil {
+		return err
+	}
+
+	regNum := t.RegNum()
+
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		added int
+		errs  []error
+	)
+
+	for i := 0; i < concurrentAdds; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := t.Storage.AddCar(ctx, &models.PreCar{OwnerID: person.ID, RegNum: regNum, Mark: "Lada",
+				Model: "Vesta", Year: 2002})
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err == nil {
+				added++
+			} else {
+				errs = append(errs, expectConstraint(err, "car_reg_num_key"))
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return errors.Join(append(errs, expectEqual("count of added cars", added, 1))...)
+}
+
+func missingCar(ctx context.Context, t *T) error {
+	_, err := t.Storage.GetCar(ctx, missingID)
+
+	return errors.Join(
+		expectError(err, carrepo.ErrCarNotFound),
+		expectError(t.Storage.DeleteCar(ctx, missingID), carrepo.ErrNoAffectedCarRows),
+		expectError(t.Storage.UpdateCar(ctx, missingID, map[string]interface{}{"mark": "Lada"}),
+			carrepo.ErrNoAffectedCarRows),
+	)
+}
+
+func updateCar(ctx context.Context, t *T) error {
+	person, err := t.AddPerson(ctx)
+	if err != nil {
+		return err
+	}
+
+	newOwner, err := t.AddPerson(ctx)
+	if err != nil {
+		return err
+	}
+
+	car, err := t.AddCar(ctx, person.ID, "Lada", "Vesta", 2002)
+	if err != nil {
+		return err
+	}
+
+	if err := expectError(t.Storage.UpdateCar(ctx, car.ID, map[string]interface{}{}),
+		carrepo.ErrNoUpdateFields); err != nil {
+		return err
+	}
+
+	regNum := t.RegNum()
+
+	err = t.Storage.UpdateCar(ctx, car.ID, map[string]interface{}{"owner_id": newOwner.ID, "reg_num": regNum,
+		"mark": "Toyota", "model": "Camry", "year": uint64(2010)})
+	if err != nil {
+		return fmt.Errorf("update car: %w", err)
+	}
+
+	updated, err := t.Storage.GetCar(ctx, car.ID)
+	if err != nil {
+		return fmt.Errorf("get car: %w", err)
+	}
+
+	return errors.Join(
+		expectEqual("owner_id", updated.OwnerID, newOwner.ID),
+		expectEqual("reg_num", updated.RegNum, regNum),
+		expectEqual("mark", updated.Mark, "Toyota"),
+		expectEqual("model", updated.Model, "Camry"),
+		expectEqual("year", updated.Year, 2010),
+		expectEqual("created_at", updated.CreatedAt.Unix(), car.CreatedAt.Unix()),
+	)
+}
+
+func invalidUpdateKeepsCar(ctx context.Context, t *T) error {
+	person, err := t.AddPerson(ctx)
+	if err != nil {
+		return err
+	}
+
+	car, err := t.AddCar(ctx, person.ID, "Lada", "Vesta", 2002)
+	if err != nil {
+		return err
+	}
+
+	other, err := t.AddCar(ctx, person.ID, "Lada", "Granta", 2015)
+	if err != nil {
+		return err
+	}
+
+	cases := []struct {
+		updateFields map[string]interface{}
+		constraint   string
+	}{
+		{updateFields: map[string]interface{}{"mark": "Toyota", "reg_num": other.RegNum}, constraint: "car_reg_num_key"},
+		{updateFields: map[string]interface{}{"mark": "Toyota", "owner_id": uint64(missingID)},
+			constraint: "car_owner_id_fkey"},
+		{updateFields: map[string]interface{}{"mark": "Toyota", "year": uint64(1800)}, constraint: "correct_year"},
+		{updateFields: map[string]interface{}{"mark": "Toyota", "model": ""}, constraint: "car_model_check"},
+	}
+
+	var errs []error
+
+	for _, c := range cases {
+		errs = append(errs, expectConstraint(t.Storage.UpdateCar(ctx, car.ID, c.updateFields), c.constraint))
+	}
+
+	kept, err := t.Storage.GetCar(ctx, car.ID)
+	if err != nil {
+		return fmt.Errorf("get car: %w", err)
+	}
+
+	return errors.Join(append(errs,
+		expectEqual("reg_num", kept.RegNum, car.RegNum),
+		expectEqual("mark", kept.Mark, car.Mark),
+		expectEqual("model", kept.Model, car.Model),
+		expectEqual("owner_id", kept.OwnerID, car.OwnerID),
+		expectEqual("year", kept.Year, car.Year),
+	)...)
+}
+
+func deleteCar(ctx context.Context, t *T) error {
+	person, err := t.AddPerson(ctx)
+	if err != nil {
+		return err
+	}
+
+	car, err := t.AddCar(ctx, person.ID, "Lada", "Vesta", 2002)
+	if err != nil {
+		return err
+	}
+
+	if err := t.Storage.DeleteCar(ctx, car.ID); err != nil {
+		return fmt.Errorf("delete car: %w", err)
+	}
+
+	_, err = t.Storage.GetCar(ctx, car.ID)
+
+	return errors.Join(
+		expectError(err, carrepo.ErrCarNotFound),
+		expectError(t.Storage.DeleteCar(ctx, car.ID), carrepo.ErrNoAffectedCarRows),
+	)
+}
+
+func deletePersonCascades(ctx context.Context, t *T) error {
+	person, err := t.AddPerson(ctx)
+	if err != nil {
+		return err
+	}
+
+	other, err := t.AddPerson(ctx)
+	if err != nil {
+		return err
+	}
+
+	car, err := t.AddCar(ctx, person.ID, "Lada", "Vesta", 2002)
+	if err != nil {
+		return err
+	}
+
+	otherCar, err := t.AddCar(ctx, other.ID, "Lada", "Vesta", 2002)
+	if err != nil {
+		return err
+	}
+
+	if err := t.Storage.DeletePerson(ctx, person.ID); err != nil {
+		return fmt.Errorf("delete person: %w", err)
+	}
+
+	_, getPersonErr := t.Storage.GetPerson(ctx, person.ID)
+	_, getCarErr := t.Storage.GetCar(ctx, car.ID)
+	_, getOtherCarErr := t.Storage.GetCar(ctx, otherCar.ID)
+
+	return errors.Join(
+		expectError(getPersonErr, peoplerepo.ErrPeopleNotFound),
+		expectError(getCarErr, carrepo.ErrCarNotFound),
+		getOtherCarErr,
+	)
+}
+
+func filterCars(ctx context.Context, t *T) error {
+	person, err := t.AddPerson(ctx)
+	if err != nil {
+		return err
+	}
+
+	other, err := t.AddPerson(ctx)
+	if err != nil {
+		return err
+	}
+
+	vesta, err := t.AddCar(ctx, person.ID, "Lada", "Vesta", 2002)
+	if err != nil {
+		return err
+	}
+
+	granta, err := t.AddCar(ctx, person.ID, "Lada", "Granta", 2015)
+	if err != nil {
+		return err
+	}
+
+	camry, err := t.AddCar(ctx, person.ID, "Toyota", "Camry", 2010)
+	if err != nil {
+		return err
+	}
+
+	if _, err := t.AddCar(ctx, other.ID, "Lada", "Vesta", 2002); err != nil {
+		return err
+	}
+
+	cases := []struct {
+		mark     string
+		model    string
+		expected []uint64
+	}{
+		{mark: "", model: "", expected: []uint64{vesta.ID, granta.ID, camry.ID}},
+		{mark: "Lada", model: "", expected: []uint64{vesta.ID, granta.ID}},
+		{mark: "", model: "Camry", expected: []uint64{camry.ID}},
+		{mark: "Lada", model: "Vesta", expected: []uint64{vesta.ID}},
+		{mark: "Toyota", model: "Vesta", expected: nil},
+	}
+
+	var errs []error
+
+	for _, c := range cases {
+		errs = append(errs, expectList(ctx, t, fmt.Sprintf("mark=%q model=%q", c.mark, c.model),
+			listQuery{limit: 10, offset: 0, model: c.model, mark: c.mark, ownerID: person.ID, sortType: unsorted},
+			c.expected))
+	}
+
+	return errors.Join(errs...)
+}
+
+func sortAndPaginateCars(ctx context.Context, t *T) error {
+	person, err := t.AddPerson(ctx)
+	if err != nil {
+		return err
+	}
+
+	var ids []uint64
+
+	for _, year := range []uint64{2010, 2002, 2015, 2010} {
+		car, err := t.AddCar(ctx, person.ID, "Lada", "Vesta", year)
+		if err != nil {
+			return err
+		}
+
+		ids = append(ids, car.ID)
+	}
+
+	all := listQuery{limit: 10, offset: 0, model: "", mark: "", ownerID: person.ID, sortType: byYearDESC}
+	asc := all
+	asc.sortType = byYearASC
+	unsortedAll := all
+	unsortedAll.sortType = unsorted
+
+	page := all
+	page.limit, page.offset = 2, 1
+	lastPage := all
+	lastPage.limit, lastPage.offset = 2, 3
+	pastEnd := all
+	pastEnd.offset = 4
+	empty := all
+	empty.limit = 0
+
+	// cars of same year are ordered by id
+	return errors.Join(
+		expectList(ctx, t, "by year desc", all, []uint64{ids[2], ids[0], ids[3], ids[1]}),
+		expectList(ctx, t, "by year asc", asc, []uint64{ids[1], ids[0], ids[3], ids[2]}),
+		expectList(ctx, t, "unsorted", unsortedAll, ids),
+		expectList(ctx, t, "page", page, []uint64{ids[0], ids[3]}),
+		expectList(ctx, t, "last page", lastPage, []uint64{ids[1]}),
+		expectList(ctx, t, "offset past end", pastEnd, nil),
+		expectList(ctx, t, "zero limit", empty, nil),
+	)
+}
+
+func filterValuesAreNotSQL(ctx context.Context, t *T) error {
+	person, err := t.AddPerson(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, err := t.AddCar(ctx, person.ID, "Lada", "Vesta", 2002); err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for _, injection := range []string{"'' OR 1=1", "'x' OR 'a'='a'", "Lada' --", "Lada; DELETE FROM car"} {
+		errs = append(errs,
+			expectList(ctx, t, "mark "+injection, listQuery{limit: 10, offset: 0, model: "", mark: injection,
+				ownerID: person.ID, sortType: byYearDESC}, nil),
+			expectList(ctx, t, "model "+injection, listQuery{limit: 10, offset: 0, model: injection, mark: "",
+				ownerID: person.ID, sortType: byYearDESC}, nil),
+		)
+	}
+
+	return errors.Join(errs...)
+}
+
+// listQuery is arguments of GetCarsList.
+type listQuery struct {
+	limit    uint64
+	offset   uint64
+	model    string
+	mark     string
+	ownerID  uint64
+	sortType uint64
+}
+
+// expectList checks that GetCarsList returns cars with expected ids in that order.
+func expectList(ctx context.Context, t *T, what string, query listQuery, expected []uint64) error {
+	cars, err := t.Storage.GetCarsList(ctx, query.limit, query.offset, query.model, query.mark, query.ownerID,
+		query.sortType)
+	if err != nil {
+		return fmt.Errorf("list %s: %w", what, err)
+	}
+
+	got := make([]uint64, 0, len(cars))
+	for _, car := range cars {
+		got = append(got, car.ID)
+	}
+
+	return expectEqual("ids of list "+what, fmt.Sprint(got), fmt.Sprint(expected))
+}
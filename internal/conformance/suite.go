@@ -0,0 +1,152 @@
+// Package conformance checks that storages of people and cars behave the same: tests of every backend run
+// the same cases with RunConformance, so memory storage can't drift from postgres repositories.
+package conformance
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	carusecases "github.com/SanExpett/auto-catalog/internal/car/usecases"
+	peopleusecases "github.com/SanExpett/auto-catalog/internal/people/usecases"
+	"github.com/SanExpett/auto-catalog/pkg/models"
+	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
+	"math"
+	"sync/atomic"
+	"testing"
+)
+
+const (
+	// missingID is id which storages never give out
+	missingID = math.MaxInt64
+	// runIDBytes are random bytes of run in registration numbers, 4 digits of number complete them to 9 characters
+	runIDBytes = 2
+)
+
+var ErrMismatch = errors.New("storage behaves differently")
+
+// Storage is storage of people and cars checked by cases.
+type Storage interface {
+	carusecases.ICarStorage
+	peopleusecases.IPeopleStorage
+}
+
+// Case is independent check of storage. It creates its own people and cars with unique registration
+// numbers and deletes them afterwards, so cases may run against database with other data.
+type Case struct {
+	Name string
+	Run  func(ctx context.Context, t *T) error
+}
+
+// T gives case access to storage and helpers which create and delete data of case.
+type T struct {
+	Storage Storage
+
+	// runID and regNums make registration numbers unique
+	runID     string
+	regNums   *atomic.Uint64
+	personIDs []uint64
+}
+
+// RunConformance runs every case of Cases as subtest of t against storage returned by newStorage, which is
+// called once per case. Storage may be shared by cases: they don't depend on data of each other.
+func RunConformance(t *testing.T, newStorage func() Storage) {
+	t.Helper()
+
+	runID := make([]byte, runIDBytes)
+	if _, err := rand.Read(runID); err != nil {
+		t.Fatalf("run id: %v", err)
+	}
+
+	var regNums atomic.Uint64
+
+	for _, c := range Cases() {
+		c := c
+
+		t.Run(c.Name, func(t *testing.T) {
+			ctx := context.Background()
+			ct := &T{Storage: newStorage(), runID: hex.EncodeToString(runID), regNums: &regNums, personIDs: nil}
+
+			err := c.Run(ctx, ct)
+			if cleanupErr := ct.cleanup(ctx); cleanupErr != nil {
+				err = errors.Join(err, fmt.Errorf("cleanup: %w", cleanupErr))
+			}
+
+			if err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// AddPerson adds person which is deleted with its cars after case.
+func (t *T) AddPerson(ctx context.Context) (*models.People, error) {
+	person, err := t.Storage.AddPerson(ctx, &models.PrePeople{Name: "Ivan", Surname: "Ivanov", Patronymic: "Ivanovich"})
+	if err != nil {
+		return nil, fmt.Errorf("add person: %w", err)
+	}
+
+	t.personIDs = append(t.personIDs, person.ID)
+
+	return person, nil
+}
+
+// AddCar adds car of person with new registration number.
+func (t *T) AddCar(ctx context.Context, ownerID uint64, mark string, model string, year uint64) (*models.Car, error) {
+	car, err := t.Storage.AddCar(ctx, &models.PreCar{OwnerID: ownerID, RegNum: t.RegNum(), Mark: mark, Model: model,
+		Year: year})
+	if err != nil {
+		return nil, fmt.Errorf("add car: %w", err)
+	}
+
+	return car, nil
+}
+
+// RegNum returns registration number which is not used by other cases and runs.
+func (t *T) RegNum() string {
+	return fmt.Sprintf("T%s%04d", t.runID, t.regNums.Add(1))
+}
+
+func (t *T) cleanup(ctx context.Context) error {
+	var errs []error
+
+	for _, personID := range t.personIDs {
+		if _, err := t.Storage.GetPerson(ctx, personID); err != nil {
+			continue
+		}
+
+		if err := t.Storage.DeletePerson(ctx, personID); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// expectError checks that err is target or wraps it.
+func expectError(err error, target error) error {
+	if !errors.Is(err, target) {
+		return fmt.Errorf("%w: expected error %q, got %v", ErrMismatch, target, err)
+	}
+
+	return nil
+}
+
+// expectConstraint checks that err is violation of constraint.
+func expectConstraint(err error, constraint string) error {
+	var constraintErr *myerrors.ConstraintError
+	if !errors.As(err, &constraintErr) || constraintErr.Constraint != constraint {
+		return fmt.Errorf("%w: expected violation of %s, got %v", ErrMismatch, constraint, err)
+	}
+
+	return nil
+}
+
+func expectEqual[V comparable](what string, got V, expected V) error {
+	if got != expected {
+		return fmt.Errorf("%w: %s is %v, expected %v", ErrMismatch, what, got, expected)
+	}
+
+	return nil
+}
@@ -0,0 +1,220 @@
+package contract
+
+import "net/http"
+
+// Auth is how case authenticates its request.
+type Auth int
+
+const (
+	// AuthAdmin sends API key with admin scope
+	AuthAdmin Auth = iota
+	// AuthReader sends API key with cars:read and people:read scopes
+	AuthReader
+	// AuthNone sends request anonymously
+	AuthNone
+	// AuthSession sends cookie and anti-CSRF token of session of last login case
+	AuthSession
+	// AuthSessionWithoutCSRF sends cookie of session of last login case without anti-CSRF token
+	AuthSessionWithoutCSRF
+)
+
+// Case is request to API method described in specification by Path and Method. Cases are run in order
+// on the same storage, so they may use data created by previous cases.
+type Case struct {
+	Name   string
+	Method string
+	Path   string
+	// DocumentedMethod is method of operation which responses are used for check, if Method is
+	// not documented for Path on purpose
+	DocumentedMethod string
+	// Params are values of path parameters like {id} of Path
+	Params map[string]string
+	Query  map[string]string
+	Body   string
+	Auth   Auth
+	// Revalidate sends If-None-Match with ETag of last response which had it
+	Revalidate bool
+	Status     int
+}
+
+const statusErrLegacy = 222
+
+func Cases() []Case {
+	return append(append(append(casesV1(), casesV2()...), casesAuth()...), casesAdmin()...)
+}
+
+// casesV1 check legacy API, its errors are sent with status 222 except errors of authentication,
+// rate limit and overload.
+func casesV1() []Case {
+	return []Case{
+		{Name: "add person", Method: http.MethodPost, Path: "/v1/people/add",
+			Body: `{"name":"Ivan","surname":"Ivanov","patronymic":"Ivanovich"}`, Status: http.StatusOK},
+		{Name: "add invalid person", Method: http.MethodPost, Path: "/v1/people/add",
+			Body: `{"name":""}`, Status: statusErrLegacy},
+		{Name: "add person without patronymic", Method: http.MethodPost, Path: "/v1/people/add",
+			Body: `{"name":"Petr","surname":"Petrov"}`, Status: http.StatusOK},
+		{Name: "add person anonymously", Method: http.MethodPost, Path: "/v1/people/add",
+			Body: `{"name":"Petr","surname":"Petrov"}`, Auth: AuthNone, Status: http.StatusUnauthorized},
+		{Name: "add person without scope", Method: http.MethodPost, Path: "/v1/people/add",
+			Body: `{"name":"Petr","surname":"Petrov"}`, Auth: AuthReader, Status: http.StatusForbidden},
+		{Name: "get person", Method: http.MethodGet, Path: "/v1/people/get",
+			Query: map[string]string{"id": "1"}, Status: http.StatusOK},
+		{Name: "get missing person", Method: http.MethodGet, Path: "/v1/people/get",
+			Query: map[string]string{"id": "100"}, Status: statusErrLegacy},
+
+		{Name: "add car", Method: http.MethodPost, Path: "/v1/car/add",
+			Body:   `{"owner_id":1,"reg_num":"X123XX150","mark":"Lada","model":"Vesta","year":2002}`,
+			Status: http.StatusOK},
+		{Name: "add car of missing person", Method: http.MethodPost, Path: "/v1/car/add",
+			Body: `{"owner_id":100,"reg_num":"X123XX151","mark":"Lada","model":"Vesta"}`, Status: statusErrLegacy},
+		{Name: "add invalid car", Method: http.MethodPost, Path: "/v1/car/add",
+			Body: `{"reg_num":"bad"}`, Status: statusErrLegacy},
+		{Name: "get car", Method: http.MethodGet, Path: "/v1/car/get",
+			Query: map[string]string{"id": "1"}, Status: http.StatusOK},
+		{Name: "get car by reader", Method: http.MethodGet, Path: "/v1/car/get",
+			Query: map[string]string{"id": "1"}, Auth: AuthReader, Status: http.StatusOK},
+		{Name: "get missing car", Method: http.MethodGet, Path: "/v1/car/get",
+			Query: map[string]string{"id": "100"}, Status: statusErrLegacy},
+		{Name: "get method with wrong http method", Method: http.MethodPost, Path: "/v1/car/get",
+			DocumentedMethod: http.MethodGet, Query: map[string]string{"id": "1"}, Status: http.StatusMethodNotAllowed},
+		{Name: "get cars list of owner", Method: http.MethodGet, Path: "/v1/car/get_list",
+			Query:  map[string]string{"limit": "10", "offset": "0", "owner_id": "1", "sort_by_year_type": "1"},
+			Status: http.StatusOK},
+		{Name: "get empty cars list", Method: http.MethodGet, Path: "/v1/car/get_list",
+			Query: map[string]string{"owner_id": "2"}, Status: http.StatusOK},
+		{Name: "update part of car", Method: http.MethodPatch, Path: "/v1/car/update",
+			Query: map[string]string{"id": "1"}, Body: `{"mark":"Toyota"}`, Status: http.StatusOK},
+		{Name: "update car", Method: http.MethodPut, Path: "/v1/car/update", Query: map[string]string{"id": "1"},
+			Body:   `{"owner_id":2,"reg_num":"X123XX150","mark":"Toyota","model":"Camry","year":2010}`,
+			Status: http.StatusOK},
+		{Name: "update car with not full data", Method: http.MethodPut, Path: "/v1/car/update",
+			Query: map[string]string{"id": "1"}, Body: `{"mark":"Toyota"}`, Status: statusErrLegacy},
+		{Name: "delete car without scope", Method: http.MethodDelete, Path: "/v1/car/delete",
+			Query: map[string]string{"id": "1"}, Auth: AuthReader, Status: http.StatusForbidden},
+		{Name: "delete car", Method: http.MethodDelete, Path: "/v1/car/delete",
+			Query: map[string]string{"id": "1"}, Status: http.StatusOK},
+		{Name: "delete missing car", Method: http.MethodDelete, Path: "/v1/car/delete",
+			Query: map[string]string{"id": "1"}, Status: statusErrLegacy},
+
+		{Name: "delete person anonymously", Method: http.MethodDelete, Path: "/v1/people/delete",
+			Query: map[string]string{"id": "2"}, Auth: AuthNone, Status: http.StatusUnauthorized},
+		{Name: "delete person", Method: http.MethodDelete, Path: "/v1/people/delete",
+			Query: map[string]string{"id": "2"}, Status: http.StatusOK},
+		{Name: "delete missing person", Method: http.MethodDelete, Path: "/v1/people/delete",
+			Query: map[string]string{"id": "2"}, Status: statusErrLegacy},
+	}
+}
+
+// casesV2 check resource-oriented API with problem+json errors, they continue with person 1 left by casesV1.
+func casesV2() []Case {
+	return []Case{
+		{Name: "v2 add person", Method: http.MethodPost, Path: "/v2/people",
+			Body: `{"name":"Anna","surname":"Sidorova"}`, Status: http.StatusCreated},
+		{Name: "v2 add invalid person", Method: http.MethodPost, Path: "/v2/people",
+			Body: `{"name":""}`, Status: http.StatusUnprocessableEntity},
+		{Name: "v2 add person anonymously", Method: http.MethodPost, Path: "/v2/people",
+			Body: `{"name":"Anna","surname":"Sidorova"}`, Auth: AuthNone, Status: http.StatusUnauthorized},
+		{Name: "v2 add person without scope", Method: http.MethodPost, Path: "/v2/people",
+			Body: `{"name":"Anna","surname":"Sidorova"}`, Auth: AuthReader, Status: http.StatusForbidden},
+		{Name: "v2 get person", Method: http.MethodGet, Path: "/v2/people/{id}",
+			Params: map[string]string{"id": "3"}, Status: http.StatusOK},
+		{Name: "v2 get missing person", Method: http.MethodGet, Path: "/v2/people/{id}",
+			Params: map[string]string{"id": "100"}, Status: http.StatusNotFound},
+
+		{Name: "v2 add car", Method: http.MethodPost, Path: "/v2/cars",
+			Body:   `{"owner_id":3,"reg_num":"A123BC777","mark":"Lada","model":"Niva","year":1885}`,
+			Status: http.StatusCreated},
+		{Name: "v2 add car with registered number", Method: http.MethodPost, Path: "/v2/cars",
+			Body:   `{"owner_id":3,"reg_num":"A123BC777","mark":"Lada","model":"Niva","year":1977}`,
+			Status: http.StatusConflict},
+		{Name: "v2 add invalid car", Method: http.MethodPost, Path: "/v2/cars",
+			Body: `{"reg_num":"bad","year":1884}`, Status: http.StatusUnprocessableEntity},
+		{Name: "v2 get car", Method: http.MethodGet, Path: "/v2/cars/{id}",
+			Params: map[string]string{"id": "2"}, Status: http.StatusOK},
+		{Name: "v2 get unchanged car", Method: http.MethodGet, Path: "/v2/cars/{id}",
+			Params: map[string]string{"id": "2"}, Revalidate: true, Status: http.StatusNotModified},
+		{Name: "v2 get missing car", Method: http.MethodGet, Path: "/v2/cars/{id}",
+			Params: map[string]string{"id": "100"}, Status: http.StatusNotFound},
+		{Name: "v2 get car with wrong http method", Method: http.MethodPost, Path: "/v2/cars/{id}",
+			DocumentedMethod: http.MethodGet, Params: map[string]string{"id": "2"},
+			Status: http.StatusMethodNotAllowed},
+		{Name: "v2 get cars list", Method: http.MethodGet, Path: "/v2/cars",
+			Query: map[string]string{"mark": "Lada", "sort_by_year_type": "1"}, Status: http.StatusOK},
+		{Name: "v2 get cars of owner", Method: http.MethodGet, Path: "/v2/people/{owner_id}/cars",
+			Params: map[string]string{"owner_id": "3"}, Query: map[string]string{"limit": "5"}, Status: http.StatusOK},
+		{Name: "v2 update part of car", Method: http.MethodPatch, Path: "/v2/cars/{id}",
+			Params: map[string]string{"id": "2"}, Body: `{"model":"Granta"}`, Status: http.StatusOK},
+		{Name: "v2 update car", Method: http.MethodPut, Path: "/v2/cars/{id}", Params: map[string]string{"id": "2"},
+			Body:   `{"owner_id":1,"reg_num":"A123BC777","mark":"Lada","model":"Granta","year":2020}`,
+			Status: http.StatusOK},
+		{Name: "v2 update missing car", Method: http.MethodPatch, Path: "/v2/cars/{id}",
+			Params: map[string]string{"id": "100"}, Body: `{"model":"Granta"}`, Status: http.StatusNotFound},
+		{Name: "v2 delete car", Method: http.MethodDelete, Path: "/v2/cars/{id}",
+			Params: map[string]string{"id": "2"}, Status: http.StatusNoContent},
+		{Name: "v2 delete missing car", Method: http.MethodDelete, Path: "/v2/cars/{id}",
+			Params: map[string]string{"id": "2"}, Status: http.StatusNotFound},
+		{Name: "v2 delete person", Method: http.MethodDelete, Path: "/v2/people/{id}",
+			Params: map[string]string{"id": "3"}, Status: http.StatusNoContent},
+		{Name: "v2 delete missing person", Method: http.MethodDelete, Path: "/v2/people/{id}",
+			Params: map[string]string{"id": "3"}, Status: http.StatusNotFound},
+	}
+}
+
+// casesAuth register user and check its session, user not linked to person sees no data.
+func casesAuth() []Case {
+	return []Case{
+		{Name: "register", Method: http.MethodPost, Path: "/v2/auth/register", Auth: AuthNone,
+			Body: `{"email":"user@example.com","password":"password1"}`, Status: http.StatusOK},
+		{Name: "register registered email", Method: http.MethodPost, Path: "/v2/auth/register", Auth: AuthNone,
+			Body: `{"email":"user@example.com","password":"password2"}`, Status: http.StatusConflict},
+		{Name: "register with short password", Method: http.MethodPost, Path: "/v2/auth/register", Auth: AuthNone,
+			Body: `{"email":"other@example.com","password":"short"}`, Status: http.StatusUnprocessableEntity},
+		{Name: "login with wrong password", Method: http.MethodPost, Path: "/v2/auth/login", Auth: AuthNone,
+			Body: `{"email":"user@example.com","password":"password2"}`, Status: http.StatusUnauthorized},
+		{Name: "login", Method: http.MethodPost, Path: "/v2/auth/login", Auth: AuthNone,
+			Body: `{"email":"user@example.com","password":"password1"}`, Status: http.StatusOK},
+		{Name: "get current user", Method: http.MethodGet, Path: "/v2/auth/me", Auth: AuthSession,
+			Status: http.StatusOK},
+		{Name: "get anti-CSRF token", Method: http.MethodGet, Path: "/v2/auth/csrf", Auth: AuthSession,
+			Status: http.StatusOK},
+		{Name: "get person by user without person", Method: http.MethodGet, Path: "/v2/people/{id}",
+			Params: map[string]string{"id": "1"}, Auth: AuthSession, Status: http.StatusNotFound},
+		{Name: "add car by user", Method: http.MethodPost, Path: "/v2/cars", Auth: AuthSession,
+			Body:   `{"owner_id":1,"reg_num":"B234CE777","mark":"Lada","model":"Niva"}`,
+			Status: http.StatusForbidden},
+		{Name: "logout without anti-CSRF token", Method: http.MethodPost, Path: "/v2/auth/logout",
+			Auth: AuthSessionWithoutCSRF, Status: http.StatusForbidden},
+		{Name: "logout", Method: http.MethodPost, Path: "/v2/auth/logout", Auth: AuthSession, Status: http.StatusOK},
+		{Name: "get current user after logout", Method: http.MethodGet, Path: "/v2/auth/me", Auth: AuthSession,
+			Status: http.StatusUnauthorized},
+		{Name: "get anti-CSRF token anonymously", Method: http.MethodGet, Path: "/v2/auth/csrf", Auth: AuthNone,
+			Status: http.StatusUnauthorized},
+	}
+}
+
+// casesAdmin check admin endpoints and rate limit which they set, limits are turned off at the end.
+func casesAdmin() []Case {
+	return []Case{
+		{Name: "get log level", Method: http.MethodGet, Path: "/admin/log/level", Status: http.StatusOK},
+		{Name: "get log level without scope", Method: http.MethodGet, Path: "/admin/log/level", Auth: AuthReader,
+			Status: http.StatusForbidden},
+		{Name: "get log level anonymously", Method: http.MethodGet, Path: "/admin/log/level", Auth: AuthNone,
+			Status: http.StatusUnauthorized},
+		{Name: "set log level", Method: http.MethodPut, Path: "/admin/log/level", Body: `{"level":"info"}`,
+			Status: http.StatusOK},
+		{Name: "set invalid log level", Method: http.MethodPut, Path: "/admin/log/level", Body: `{"level":"fatal"}`,
+			Status: http.StatusUnprocessableEntity},
+		{Name: "get rate limits", Method: http.MethodGet, Path: "/admin/ratelimit", Status: http.StatusOK},
+		{Name: "set invalid rate limit", Method: http.MethodPut, Path: "/admin/ratelimit",
+			Body: `{"read":"many"}`, Status: http.StatusUnprocessableEntity},
+		{Name: "set rate limit", Method: http.MethodPut, Path: "/admin/ratelimit", Body: `{"read":"1/1h"}`,
+			Status: http.StatusOK},
+		{Name: "get cars list within rate limit", Method: http.MethodGet, Path: "/v2/cars", Status: http.StatusOK},
+		{Name: "get cars list over rate limit", Method: http.MethodGet, Path: "/v2/cars",
+			Status: http.StatusTooManyRequests},
+		{Name: "get legacy cars list over rate limit", Method: http.MethodGet, Path: "/v1/car/get_list",
+			Status: http.StatusTooManyRequests},
+		{Name: "turn rate limit off", Method: http.MethodPut, Path: "/admin/ratelimit", Body: `{"read":"off"}`,
+			Status: http.StatusOK},
+	}
+}
@@ -0,0 +1,39 @@
+package contract_test
+
+import (
+	"context"
+	"github.com/SanExpett/auto-catalog/docs"
+	"github.com/SanExpett/auto-catalog/internal/contract"
+	"github.com/SanExpett/auto-catalog/pkg/my_logger"
+	"go.uber.org/zap/zapcore"
+	"os"
+	"testing"
+)
+
+func TestContract(t *testing.T) {
+	spec, err := docs.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger, err := my_logger.New(my_logger.Config{
+		Level:            zapcore.InfoLevel,
+		Encoding:         my_logger.EncodingJSON,
+		Sampling:         false,
+		Caller:           true,
+		OutputPaths:      []string{os.DevNull},
+		ErrorOutputPaths: []string{"stderr"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler, err := contract.NewHandler(context.Background(), logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, problem := range contract.Run(spec, handler, contract.Cases()) {
+		t.Error(problem)
+	}
+}
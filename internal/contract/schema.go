@@ -0,0 +1,117 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/SanExpett/auto-catalog/docs"
+	"sort"
+	"strings"
+	"time"
+)
+
+const definitionsPrefix = "#/definitions/"
+
+// validateValue checks value decoded by json.Decoder with UseNumber against schema. Properties which
+// are not described in schema are reported too, because they mean drift of specification.
+func validateValue(spec *docs.Spec, schema *docs.Schema, value any, path string) []string {
+	if schema == nil {
+		return nil
+	}
+
+	if schema.Ref != "" {
+		definition, ok := spec.Definitions[strings.TrimPrefix(schema.Ref, definitionsPrefix)]
+		if !ok {
+			return []string{fmt.Sprintf("%s: unknown definition %s", path, schema.Ref)}
+		}
+
+		return validateValue(spec, definition, value, path)
+	}
+
+	switch schema.Type {
+	case "object":
+		return validateObject(spec, schema, value, path)
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected array, got %s", path, describe(value))}
+		}
+
+		var problems []string
+		for i, item := range items {
+			problems = append(problems, validateValue(spec, schema.Items, item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+
+		return problems
+	case "integer":
+		number, ok := value.(json.Number)
+		if _, err := number.Int64(); !ok || err != nil {
+			return []string{fmt.Sprintf("%s: expected integer, got %s", path, describe(value))}
+		}
+	case "number":
+		if _, ok := value.(json.Number); !ok {
+			return []string{fmt.Sprintf("%s: expected number, got %s", path, describe(value))}
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return []string{fmt.Sprintf("%s: expected boolean, got %s", path, describe(value))}
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected string, got %s", path, describe(value))}
+		}
+
+		if _, err := time.Parse(time.RFC3339, str); schema.Format == "date-time" && err != nil {
+			return []string{fmt.Sprintf("%s: expected date-time, got %q", path, str)}
+		}
+	}
+
+	return nil
+}
+
+func validateObject(spec *docs.Spec, schema *docs.Schema, value any, path string) []string {
+	object, ok := value.(map[string]any)
+	if !ok {
+		return []string{fmt.Sprintf("%s: expected object, got %s", path, describe(value))}
+	}
+
+	var problems []string
+
+	for _, name := range schema.Required {
+		if _, ok := object[name]; !ok {
+			problems = append(problems, fmt.Sprintf("%s: required property %s is missing", path, name))
+		}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		property, ok := schema.Properties[name]
+		if !ok && schema.AdditionalProperties != nil {
+			property, ok = schema.AdditionalProperties, true
+		}
+
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s: property %s is not described in specification", path, name))
+
+			continue
+		}
+
+		problems = append(problems, validateValue(spec, property, object[name], path+"."+name)...)
+	}
+
+	return problems
+}
+
+func describe(value any) string {
+	if value == nil {
+		return "null"
+	}
+
+	return fmt.Sprintf("%T %v", value, value)
+}
@@ -0,0 +1,397 @@
+// Package contract checks that API handlers behave as docs/swagger.yaml describes: requests of
+// cases and responses of handlers are validated against specification, so any drift is reported.
+// It is run by go test, make contract-check and while building docker image.
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/SanExpett/auto-catalog/docs"
+	apikeyusecases "github.com/SanExpett/auto-catalog/internal/apikey/usecases"
+	carusecases "github.com/SanExpett/auto-catalog/internal/car/usecases"
+	healthusecases "github.com/SanExpett/auto-catalog/internal/health/usecases"
+	"github.com/SanExpett/auto-catalog/internal/memory"
+	peopleusecases "github.com/SanExpett/auto-catalog/internal/people/usecases"
+	"github.com/SanExpett/auto-catalog/internal/server/delivery"
+	"github.com/SanExpett/auto-catalog/internal/server/delivery/mux"
+	userusecases "github.com/SanExpett/auto-catalog/internal/user/usecases"
+	"github.com/SanExpett/auto-catalog/pkg/cache"
+	"github.com/SanExpett/auto-catalog/pkg/middleware"
+	"github.com/SanExpett/auto-catalog/pkg/models"
+	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
+	"github.com/SanExpett/auto-catalog/pkg/ratelimit"
+	"go.uber.org/zap"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	contentTypeText = "text/plain"
+
+	// adminKey and readerKey are keys accepted by handler of NewHandler, reader may only read
+	adminKey  = "contract"
+	readerKey = "contract-reader"
+
+	sessionIdleTimeout = 30 * time.Minute
+	sessionMaxAge      = 24 * time.Hour
+
+	cacheSize = 100
+	cacheTTL  = time.Minute
+)
+
+// staticAuthenticator accepts only adminKey and readerKey.
+type staticAuthenticator struct{}
+
+func (staticAuthenticator) Authenticate(_ context.Context, key string) (*models.APIKey, error) {
+	switch key {
+	case adminKey:
+		return &models.APIKey{ID: 1, Name: key, Prefix: key, Scopes: []models.Scope{models.ScopeAdmin}}, nil //nolint:exhaustruct
+	case readerKey:
+		return &models.APIKey{ID: 2, Name: key, Prefix: key, //nolint:exhaustruct
+			Scopes: []models.Scope{models.ScopeCarsRead, models.ScopePeopleRead}}, nil
+	default:
+		return nil, fmt.Errorf(myerrors.ErrTemplate, apikeyusecases.ErrInvalidAPIKey)
+	}
+}
+
+// session is cookie session of user logged in by one of cases, it is used by cases with AuthSession.
+type session struct {
+	token     string
+	csrfToken string
+}
+
+// update takes session of login and forgets session of logout.
+func (s *session) update(recorder *httptest.ResponseRecorder) {
+	for _, cookie := range recorder.Result().Cookies() {
+		if cookie.Name != delivery.CookieAuthName {
+			continue
+		}
+
+		if cookie.MaxAge < 0 || cookie.Value == "" {
+			*s = session{token: "", csrfToken: ""}
+
+			continue
+		}
+
+		s.token = cookie.Value
+	}
+
+	if csrfToken := recorder.Header().Get(delivery.HeaderCSRFToken); csrfToken != "" {
+		s.csrfToken = csrfToken
+	}
+}
+
+// NewHandler returns handler of mux.NewMux backed by in-memory storage with v1 legacy error responses,
+// as they are described in specification. Rate limits are off until cases change them by admin endpoint.
+func NewHandler(ctx context.Context, logger *zap.SugaredLogger) (http.Handler, error) {
+	storage, err := memory.NewStorage()
+	if err != nil {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	// reads go through cache as in server, so cases check its invalidation too
+	peopleCache := cache.NewLRU[uint64, models.People]("people", cacheSize, cacheTTL)
+	carCache := cache.NewLRU[uint64, models.Car]("car", cacheSize, cacheTTL)
+
+	peopleStorage, err := peopleusecases.NewCachedPeopleStorage(storage, peopleCache, carCache)
+	if err != nil {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	carStorage, err := carusecases.NewCachedCarStorage(storage, carCache)
+	if err != nil {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	peopleService, err := peopleusecases.NewPeopleService(peopleStorage)
+	if err != nil {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	carService, err := carusecases.NewCarService(carStorage)
+	if err != nil {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	healthService, err := healthusecases.NewHealthService()
+	if err != nil {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	userService, err := userusecases.NewUserService(storage, storage, sessionIdleTimeout, sessionMaxAge)
+	if err != nil {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	corsPolicy, err := middleware.NewCORSPolicy([]string{"localhost:3000"}, "http://", nil, nil, 0)
+	if err != nil {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	handler, err := mux.NewMux(ctx, mux.NewConfigMux(corsPolicy, 0, "8080", true, myerrors.DefaultLang, false, true),
+		peopleService, carService, healthService, userService, staticAuthenticator{},
+		ratelimit.NewLimiter(map[ratelimit.Class]ratelimit.Limit{ratelimit.ClassRead: {}, ratelimit.ClassWrite: {}}), //nolint:exhaustruct
+		nil, logger)
+	if err != nil {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	return handler, nil
+}
+
+// Run sends cases to handler and returns all found disagreements with specification.
+// Operations of specification without cases are reported too.
+func Run(spec *docs.Spec, handler http.Handler, cases []Case) []string {
+	var problems []string
+
+	covered := make(map[string]bool)
+
+	userSession := &session{token: "", csrfToken: ""}
+
+	var etag string
+
+	for _, c := range cases {
+		documentedMethod := c.Method
+		if c.DocumentedMethod != "" {
+			documentedMethod = c.DocumentedMethod
+		} else {
+			covered[strings.ToLower(c.Method)+" "+c.Path] = true
+		}
+
+		operation, ok := spec.Paths[c.Path][strings.ToLower(documentedMethod)]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s: %s %s is not described in specification",
+				c.Name, documentedMethod, c.Path))
+
+			continue
+		}
+
+		for _, problem := range checkRequest(spec, operation, c) {
+			problems = append(problems, fmt.Sprintf("%s: request: %s", c.Name, problem))
+		}
+
+		recorder := send(spec, handler, c, userSession, etag)
+		userSession.update(recorder)
+
+		if recorder.Header().Get("ETag") != "" {
+			etag = recorder.Header().Get("ETag")
+		}
+
+		for _, problem := range checkResponse(spec, operation, c, recorder) {
+			problems = append(problems, fmt.Sprintf("%s: response: %s", c.Name, problem))
+		}
+	}
+
+	var uncovered []string
+
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			if !covered[method+" "+path] {
+				uncovered = append(uncovered, fmt.Sprintf("%s %s: no contract cases", method, path))
+			}
+		}
+	}
+
+	sort.Strings(uncovered)
+
+	return append(problems, uncovered...)
+}
+
+func send(spec *docs.Spec, handler http.Handler, c Case, userSession *session, etag string,
+) *httptest.ResponseRecorder {
+	query := url.Values{}
+	for name, value := range c.Query {
+		query.Set(name, value)
+	}
+
+	path := c.Path
+	for name, value := range c.Params {
+		path = strings.ReplaceAll(path, "{"+name+"}", url.PathEscape(value))
+	}
+
+	target := spec.BasePath + path
+	if len(query) != 0 {
+		target += "?" + query.Encode()
+	}
+
+	request := httptest.NewRequest(c.Method, target, strings.NewReader(c.Body))
+
+	switch c.Auth {
+	case AuthAdmin:
+		request.Header.Set("Authorization", "Bearer "+adminKey)
+	case AuthReader:
+		request.Header.Set("Authorization", "Bearer "+readerKey)
+	case AuthSession, AuthSessionWithoutCSRF:
+		if userSession.token != "" {
+			request.AddCookie(&http.Cookie{Name: delivery.CookieAuthName, Value: userSession.token}) //nolint:exhaustruct
+		}
+
+		if c.Auth == AuthSession && userSession.csrfToken != "" {
+			request.Header.Set(delivery.HeaderCSRFToken, userSession.csrfToken)
+		}
+	case AuthNone:
+	}
+
+	if c.Body != "" {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	if c.Revalidate {
+		request.Header.Set("If-None-Match", etag)
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	return recorder
+}
+
+func checkRequest(spec *docs.Spec, operation docs.Operation, c Case) []string {
+	var problems []string
+
+	documented := make(map[string]bool)
+
+	for _, parameter := range operation.Parameters {
+		switch parameter.In {
+		case "path":
+			value, ok := c.Params[parameter.Name]
+			if !ok {
+				problems = append(problems, fmt.Sprintf("path parameter %s is missing", parameter.Name))
+
+				continue
+			}
+
+			if _, err := strconv.ParseInt(value, 10, 64); parameter.Type == "integer" && err != nil {
+				problems = append(problems, fmt.Sprintf("path parameter %s=%s is not integer", parameter.Name, value))
+			}
+		case "query":
+			documented[parameter.Name] = true
+
+			value, ok := c.Query[parameter.Name]
+			if !ok {
+				if parameter.Required {
+					problems = append(problems, fmt.Sprintf("required query parameter %s is missing", parameter.Name))
+				}
+
+				continue
+			}
+
+			if _, err := strconv.ParseInt(value, 10, 64); parameter.Type == "integer" && err != nil {
+				problems = append(problems, fmt.Sprintf("query parameter %s=%s is not integer", parameter.Name, value))
+			}
+		case "body":
+			documented[""] = true
+
+			if c.Body == "" {
+				if parameter.Required {
+					problems = append(problems, "required body is missing")
+				}
+
+				continue
+			}
+
+			body, err := decodeJSON(c.Body)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("body is not json: %s", err.Error()))
+
+				continue
+			}
+
+			problems = append(problems, validateValue(spec, parameter.Schema, body, "body")...)
+		}
+	}
+
+	for name := range c.Query {
+		if !documented[name] {
+			problems = append(problems, fmt.Sprintf("query parameter %s is not described in specification", name))
+		}
+	}
+
+	if c.Body != "" && !documented[""] {
+		problems = append(problems, "body is not described in specification")
+	}
+
+	return problems
+}
+
+func checkResponse(spec *docs.Spec, operation docs.Operation, c Case,
+	recorder *httptest.ResponseRecorder,
+) []string {
+	if recorder.Code != c.Status {
+		return []string{fmt.Sprintf("expected status %d, got %d: %s", c.Status, recorder.Code, recorder.Body.String())}
+	}
+
+	response, ok := operation.Responses[strconv.Itoa(recorder.Code)]
+	if !ok {
+		return []string{fmt.Sprintf("status %d is not described in specification", recorder.Code)}
+	}
+
+	var problems []string
+
+	for name := range response.Headers {
+		if recorder.Header().Get(name) == "" {
+			problems = append(problems, fmt.Sprintf("header %s is missing", name))
+		}
+	}
+
+	if response.Schema == nil {
+		if recorder.Body.Len() != 0 {
+			problems = append(problems, fmt.Sprintf("expected no body, got %s", recorder.Body.String()))
+		}
+
+		return problems
+	}
+
+	mediaType, _, err := mime.ParseMediaType(recorder.Header().Get("Content-Type"))
+	if err != nil {
+		return append(problems, fmt.Sprintf("invalid content-type %q", recorder.Header().Get("Content-Type")))
+	}
+
+	if response.Schema.Type == "string" {
+		if mediaType != contentTypeText {
+			return append(problems, fmt.Sprintf("expected content-type %s, got %s", contentTypeText, mediaType))
+		}
+
+		return problems
+	}
+
+	if !contains(operation.Produces, mediaType) {
+		return append(problems, fmt.Sprintf("content-type %s is not in produces %v", mediaType, operation.Produces))
+	}
+
+	body, err := decodeJSON(recorder.Body.String())
+	if err != nil {
+		return append(problems, fmt.Sprintf("body is not json: %s", err.Error()))
+	}
+
+	return append(problems, validateValue(spec, response.Schema, body, "body")...)
+}
+
+func decodeJSON(data string) (any, error) {
+	decoder := json.NewDecoder(strings.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	return value, nil
+}
+
+func contains(items []string, item string) bool {
+	for _, current := range items {
+		if current == item {
+			return true
+		}
+	}
+
+	return false
+}
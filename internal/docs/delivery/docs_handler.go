@@ -0,0 +1,106 @@
+package delivery
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"github.com/SanExpett/auto-catalog/docs"
+	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
+	"github.com/SanExpett/auto-catalog/pkg/my_logger"
+	"html/template"
+	"net/http"
+	"sort"
+)
+
+const (
+	SpecURL = "/api/docs/openapi.yaml"
+
+	ContentTypeYAML = "application/yaml"
+	ContentTypeHTML = "text/html; charset=utf-8"
+)
+
+//go:embed explorer.html
+var explorerTemplate string
+
+type explorerOperation struct {
+	docs.Operation
+	Method string
+	Path   string
+}
+
+type explorerPage struct {
+	Info       docs.Info
+	BasePath   string
+	SpecURL    string
+	Operations []explorerOperation
+}
+
+type DocsHandler struct {
+	explorer []byte
+}
+
+func NewDocsHandler() (*DocsHandler, error) {
+	explorer, err := renderExplorer()
+	if err != nil {
+		return nil, err
+	}
+
+	return &DocsHandler{
+		explorer: explorer,
+	}, nil
+}
+
+func renderExplorer() ([]byte, error) {
+	spec, err := docs.Parse()
+	if err != nil {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	page := explorerPage{Info: spec.Info, BasePath: spec.BasePath, SpecURL: SpecURL, Operations: nil}
+
+	for path, operations := range spec.Paths {
+		for method, operation := range operations {
+			page.Operations = append(page.Operations, explorerOperation{Operation: operation, Method: method, Path: path})
+		}
+	}
+
+	sort.Slice(page.Operations, func(i, j int) bool {
+		if page.Operations[i].Path != page.Operations[j].Path {
+			return page.Operations[i].Path < page.Operations[j].Path
+		}
+
+		return page.Operations[i].Method < page.Operations[j].Method
+	})
+
+	tmpl, err := template.New("explorer").Parse(explorerTemplate)
+	if err != nil {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	var explorer bytes.Buffer
+	if err := tmpl.Execute(&explorer, page); err != nil {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	return explorer.Bytes(), nil
+}
+
+// GetSpecHandler sends swagger specification embedded in binary.
+func (d *DocsHandler) GetSpecHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", ContentTypeYAML)
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := w.Write(docs.SwaggerYAML); err != nil {
+		my_logger.FromContext(r.Context()).Errorf("in GetSpecHandler: %+v", err)
+	}
+}
+
+// GetExplorerHandler sends page which lists API methods and allows to send requests to them.
+func (d *DocsHandler) GetExplorerHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", ContentTypeHTML)
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := w.Write(d.explorer); err != nil {
+		my_logger.FromContext(r.Context()).Errorf("in GetExplorerHandler: %+v", err)
+	}
+}
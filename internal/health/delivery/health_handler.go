@@ -0,0 +1,53 @@
+package delivery
+
+import (
+	"context"
+	"github.com/SanExpett/auto-catalog/internal/health/usecases"
+	"github.com/SanExpett/auto-catalog/internal/server/delivery"
+	"github.com/SanExpett/auto-catalog/pkg/my_logger"
+	"net/http"
+)
+
+var _ IHealthService = (*usecases.HealthService)(nil)
+
+type IHealthService interface {
+	Ready(ctx context.Context) ([]usecases.CheckResult, bool)
+}
+
+// HealthHandler serves probes of orchestrator. Successful probes aren't logged, they are too frequent.
+type HealthHandler struct {
+	service IHealthService
+}
+
+func NewHealthHandler(healthService IHealthService) (*HealthHandler, error) {
+	return &HealthHandler{
+		service: healthService,
+	}, nil
+}
+
+// HealthzHandler reports that process is alive.
+func (h *HealthHandler) HealthzHandler(w http.ResponseWriter, r *http.Request) {
+	logger := my_logger.FromContext(r.Context())
+
+	w.Header().Set("Cache-Control", "no-store")
+	delivery.SendOkResponse(w, r, logger, NewHealthResponse(usecases.StatusOk))
+}
+
+// ReadyzHandler reports status and latency of every dependency, it responds 503 if any of them failed.
+func (h *HealthHandler) ReadyzHandler(w http.ResponseWriter, r *http.Request) {
+	logger := my_logger.FromContext(r.Context())
+
+	checks, ready := h.service.Ready(r.Context())
+
+	w.Header().Set("Cache-Control", "no-store")
+
+	if !ready {
+		logger.Warnf("in ReadyzHandler: not ready: %+v", checks)
+		delivery.SendStatusResponse(w, r, logger, http.StatusServiceUnavailable,
+			NewReadyResponse(usecases.StatusFail, checks))
+
+		return
+	}
+
+	delivery.SendOkResponse(w, r, logger, NewReadyResponse(usecases.StatusOk, checks))
+}
@@ -0,0 +1,24 @@
+package delivery
+
+import "github.com/SanExpett/auto-catalog/internal/health/usecases"
+
+type HealthResponse struct {
+	Status string `json:"status"`
+}
+
+func NewHealthResponse(status string) *HealthResponse {
+	return &HealthResponse{Status: status}
+}
+
+type ReadyResponse struct {
+	Status string                 `json:"status"`
+	Checks []usecases.CheckResult `json:"checks"`
+}
+
+func NewReadyResponse(status string, checks []usecases.CheckResult) *ReadyResponse {
+	if checks == nil {
+		checks = []usecases.CheckResult{}
+	}
+
+	return &ReadyResponse{Status: status, Checks: checks}
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type HealthStorage struct {
+	pool *pgxpool.Pool
+}
+
+func NewHealthStorage(pool *pgxpool.Pool) (*HealthStorage, error) {
+	return &HealthStorage{pool: pool}, nil
+}
+
+func (h *HealthStorage) Ping(ctx context.Context) error {
+	if err := h.pool.Ping(ctx); err != nil {
+		return fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	return nil
+}
+
+// MigrationVersion returns version of applied schema and whether the last migration failed halfway.
+func (h *HealthStorage) MigrationVersion(ctx context.Context) (uint64, bool, error) {
+	SQLSelectMigrationVersion := `SELECT version, dirty FROM public."schema_migrations" LIMIT 1;`
+
+	var (
+		version uint64
+		dirty   bool
+	)
+
+	if err := h.pool.QueryRow(ctx, SQLSelectMigrationVersion).Scan(&version, &dirty); err != nil {
+		return 0, false, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	return version, dirty, nil
+}
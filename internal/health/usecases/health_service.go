@@ -0,0 +1,157 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	healthrepo "github.com/SanExpett/auto-catalog/internal/health/repository"
+	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	StatusOk   = "ok"
+	StatusFail = "fail"
+
+	checkTimeout = 2 * time.Second
+)
+
+var (
+	ErrShuttingDown       = errors.New("server is shutting down")
+	ErrDirtySchema        = errors.New("last migration of schema failed, schema is dirty")
+	ErrSchemaNotMigrated  = errors.New("schema is older than application")
+	ErrSchemaNewer        = errors.New("schema is newer than application")
+	ErrCarInfoUnavailable = errors.New("car info api responded with server error")
+)
+
+var _ IHealthStorage = (*healthrepo.HealthStorage)(nil)
+
+type IHealthStorage interface {
+	Ping(ctx context.Context) error
+	MigrationVersion(ctx context.Context) (uint64, bool, error)
+}
+
+// Check is one dependency which must be available for server to be ready.
+type Check struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+type CheckResult struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+type HealthService struct {
+	checks []Check
+}
+
+func NewHealthService(checks ...Check) (*HealthService, error) {
+	return &HealthService{checks: checks}, nil
+}
+
+// Ready runs all checks concurrently and reports whether all of them passed.
+func (h *HealthService) Ready(ctx context.Context) ([]CheckResult, bool) {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	results := make([]CheckResult, len(h.checks))
+
+	var wg sync.WaitGroup
+
+	for i, check := range h.checks {
+		wg.Add(1)
+
+		go func(i int, check Check) {
+			defer wg.Done()
+
+			start := time.Now()
+			err := check.Check(ctx)
+
+			results[i] = CheckResult{
+				Name:      check.Name,
+				Status:    StatusOk,
+				LatencyMs: float64(time.Since(start)) / float64(time.Millisecond),
+			}
+
+			if err != nil {
+				results[i].Status = StatusFail
+				results[i].Error = err.Error()
+			}
+		}(i, check)
+	}
+
+	wg.Wait()
+
+	ready := true
+
+	for _, result := range results {
+		if result.Status != StatusOk {
+			ready = false
+		}
+	}
+
+	return results, ready
+}
+
+// ServerCheck fails after graceful shutdown of server has begun.
+func ServerCheck(isReady func() bool) Check {
+	return Check{Name: "server", Check: func(ctx context.Context) error {
+		if !isReady() {
+			return ErrShuttingDown
+		}
+
+		return nil
+	}}
+}
+
+func PostgresCheck(storage IHealthStorage) Check {
+	return Check{Name: "postgres", Check: storage.Ping}
+}
+
+// MigrationsCheck fails if applied schema is dirty or its version differs from latestVersion known by application.
+func MigrationsCheck(storage IHealthStorage, latestVersion uint64) Check {
+	return Check{Name: "migrations", Check: func(ctx context.Context) error {
+		version, dirty, err := storage.MigrationVersion(ctx)
+		if err != nil {
+			return fmt.Errorf(myerrors.ErrTemplate, err)
+		}
+
+		switch {
+		case dirty:
+			return fmt.Errorf("%w: version=%d", ErrDirtySchema, version)
+		case version < latestVersion:
+			return fmt.Errorf("%w: version=%d, expected=%d", ErrSchemaNotMigrated, version, latestVersion)
+		case version > latestVersion:
+			return fmt.Errorf("%w: version=%d, expected=%d", ErrSchemaNewer, version, latestVersion)
+		}
+
+		return nil
+	}}
+}
+
+// CarInfoCheck fails if Car Info API at url is unreachable or responds with 5xx status.
+func CarInfoCheck(client *http.Client, url string) Check {
+	return Check{Name: "car_info_api", Check: func(ctx context.Context) error {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return fmt.Errorf(myerrors.ErrTemplate, err)
+		}
+
+		response, err := client.Do(request)
+		if err != nil {
+			return fmt.Errorf(myerrors.ErrTemplate, err)
+		}
+		defer response.Body.Close()
+
+		if response.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("%w: status=%d", ErrCarInfoUnavailable, response.StatusCode)
+		}
+
+		return nil
+	}}
+}
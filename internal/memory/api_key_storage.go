@@ -0,0 +1,131 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	apikeyrepo "github.com/SanExpett/auto-catalog/internal/apikey/repository"
+	"github.com/SanExpett/auto-catalog/internal/server/repository"
+	"github.com/SanExpett/auto-catalog/pkg/models"
+	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
+	"github.com/jackc/pgx/v5/pgconn"
+	"slices"
+	"time"
+)
+
+const (
+	maxLenAPIKeyName = 64
+	// lastUsedPrecision limits updates of last usage time to one per key in this period
+	lastUsedPrecision = time.Minute
+)
+
+func (s *Storage) AddAPIKey(ctx context.Context, name string, prefix string, keyHash []byte,
+	scopes []models.Scope,
+) (*models.APIKey, error) {
+	err := checkConstraints([]check{
+		{name: "api_key_name_check", violated: name == ""},
+		{name: "max_len_api_key_name", violated: length(name) > maxLenAPIKeyName},
+	}, apikeyrepo.APIKeyConstraints)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, stored := range s.apiKeys {
+		if stored.keyHash == string(keyHash) {
+			return nil, fmt.Errorf(myerrors.ErrTemplate, &pgconn.PgError{ //nolint:exhaustruct
+				Code: repository.PgCodeUniqueViolation, ConstraintName: "api_key_key_hash_key"})
+		}
+	}
+
+	s.lastIDs.apiKey++
+
+	apiKey := &models.APIKey{ID: s.lastIDs.apiKey, Name: name, Prefix: prefix, Scopes: slices.Clone(scopes),
+		CreatedAt: s.now(), LastUsedAt: nil, RevokedAt: nil}
+	s.apiKeys[apiKey.ID] = &storedAPIKey{apiKey: apiKey, keyHash: string(keyHash)}
+
+	return copyAPIKey(apiKey), nil
+}
+
+// GetAPIKeyByHash returns not revoked key with hash keyHash.
+func (s *Storage) GetAPIKeyByHash(ctx context.Context, keyHash []byte) (*models.APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, stored := range s.apiKeys {
+		if stored.keyHash == string(keyHash) && stored.apiKey.RevokedAt == nil {
+			return copyAPIKey(stored.apiKey), nil
+		}
+	}
+
+	return nil, fmt.Errorf(myerrors.ErrTemplate, apikeyrepo.ErrAPIKeyNotFound)
+}
+
+func (s *Storage) GetAPIKeysList(ctx context.Context) ([]*models.APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	apiKeys := make([]*models.APIKey, 0, len(s.apiKeys))
+
+	for _, stored := range s.apiKeys {
+		apiKeys = append(apiKeys, copyAPIKey(stored.apiKey))
+	}
+
+	slices.SortFunc(apiKeys, func(a, b *models.APIKey) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return apiKeys, nil
+}
+
+func (s *Storage) RevokeAPIKey(ctx context.Context, apiKeyID uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.apiKeys[apiKeyID]
+	if !ok || stored.apiKey.RevokedAt != nil {
+		return fmt.Errorf(myerrors.ErrTemplate, apikeyrepo.ErrAPIKeyNotFound)
+	}
+
+	now := s.now()
+	stored.apiKey.RevokedAt = &now
+
+	return nil
+}
+
+// TouchAPIKey records that key is used now, time is updated not more often than lastUsedPrecision.
+func (s *Storage) TouchAPIKey(ctx context.Context, apiKeyID uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.apiKeys[apiKeyID]
+	if !ok {
+		return nil
+	}
+
+	now := s.now()
+	if stored.apiKey.LastUsedAt == nil || stored.apiKey.LastUsedAt.Before(now.Add(-lastUsedPrecision)) {
+		stored.apiKey.LastUsedAt = &now
+	}
+
+	return nil
+}
+
+func copyAPIKey(apiKey *models.APIKey) *models.APIKey {
+	copied := *apiKey
+	copied.Scopes = slices.Clone(apiKey.Scopes)
+
+	if apiKey.LastUsedAt != nil {
+		lastUsedAt := *apiKey.LastUsedAt
+		copied.LastUsedAt = &lastUsedAt
+	}
+
+	if apiKey.RevokedAt != nil {
+		revokedAt := *apiKey.RevokedAt
+		copied.RevokedAt = &revokedAt
+	}
+
+	return &copied
+}
@@ -0,0 +1,196 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	carrepo "github.com/SanExpett/auto-catalog/internal/car/repository"
+	"github.com/SanExpett/auto-catalog/internal/server/repository"
+	"github.com/SanExpett/auto-catalog/pkg/models"
+	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
+	"slices"
+)
+
+const (
+	lenRegNum      = 9
+	maxLenCarField = 256
+	minYear        = 1885
+
+	byYearDESC = 0
+	byYearASC  = 1
+)
+
+// checkCar returns error of constraint of table car which car breaks, ownership is checked by caller.
+func (s *Storage) checkCar(car *models.Car) error {
+	err := checkConstraints([]check{
+		{name: "car_mark_check", violated: car.Mark == ""},
+		{name: "car_model_check", violated: car.Model == ""},
+		{name: "car_reg_num_check", violated: car.RegNum == ""},
+		{name: "correct_year", violated: car.Year < minYear},
+		{name: "len_reg_num", violated: length(car.RegNum) != lenRegNum},
+		{name: "max_len_mark", violated: length(car.Mark) > maxLenCarField},
+		{name: "max_len_model", violated: length(car.Model) > maxLenCarField},
+	}, carrepo.CarConstraints)
+	if err != nil {
+		return err
+	}
+
+	for _, other := range s.cars {
+		if other.ID != car.ID && other.RegNum == car.RegNum {
+			return violation(repository.PgCodeUniqueViolation, "car_reg_num_key", carrepo.CarConstraints)
+		}
+	}
+
+	if _, ok := s.people[car.OwnerID]; !ok {
+		return violation(repository.PgCodeForeignKeyViolation, "car_owner_id_fkey", carrepo.CarConstraints)
+	}
+
+	return nil
+}
+
+func (s *Storage) AddCar(ctx context.Context, preCar *models.PreCar) (*models.Car, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.people[preCar.OwnerID]; !ok {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, carrepo.ErrNoPerson)
+	}
+
+	car := &models.Car{ID: s.lastIDs.car + 1, OwnerID: preCar.OwnerID, RegNum: preCar.RegNum, Mark: preCar.Mark,
+		Model: preCar.Model, Year: preCar.Year, CreatedAt: s.now()}
+
+	if err := s.checkCar(car); err != nil {
+		return nil, err
+	}
+
+	s.lastIDs.car++
+	s.cars[car.ID] = car
+
+	copyCar := *car
+
+	return &copyCar, nil
+}
+
+func (s *Storage) GetCar(ctx context.Context, carID uint64) (*models.Car, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	car, ok := s.cars[carID]
+	if !ok {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, carrepo.ErrCarNotFound)
+	}
+
+	copyCar := *car
+
+	return &copyCar, nil
+}
+
+func (s *Storage) DeleteCar(ctx context.Context, carID uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.cars[carID]; !ok {
+		return fmt.Errorf(myerrors.ErrTemplate, carrepo.ErrNoAffectedCarRows)
+	}
+
+	delete(s.cars, carID)
+
+	return nil
+}
+
+// UpdateCar sets columns of updateFields, car is left unchanged if any of them is invalid.
+func (s *Storage) UpdateCar(ctx context.Context, carID uint64, updateFields map[string]interface{}) error {
+	if len(updateFields) == 0 {
+		return carrepo.ErrNoUpdateFields
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	car, ok := s.cars[carID]
+	if !ok {
+		return fmt.Errorf(myerrors.ErrTemplate, carrepo.ErrNoAffectedCarRows)
+	}
+
+	updated := *car
+
+	for field, value := range updateFields {
+		var ok bool
+
+		switch field {
+		case "owner_id":
+			updated.OwnerID, ok = value.(uint64)
+		case "reg_num":
+			updated.RegNum, ok = value.(string)
+		case "mark":
+			updated.Mark, ok = value.(string)
+		case "model":
+			updated.Model, ok = value.(string)
+		case "year":
+			updated.Year, ok = value.(uint64)
+		default:
+			return fmt.Errorf("%w: %s", ErrUnknownColumn, field)
+		}
+
+		if !ok {
+			return fmt.Errorf("%w: %s of type %T", ErrInvalidValue, field, value)
+		}
+	}
+
+	if err := s.checkCar(&updated); err != nil {
+		return err
+	}
+
+	*car = updated
+
+	return nil
+}
+
+// GetCarsList filters and sorts cars as query of postgres repository does, cars of same year are ordered by id.
+func (s *Storage) GetCarsList(ctx context.Context, limit uint64, offset uint64, model string, mark string,
+	ownerID uint64, sortByYearType uint64,
+) ([]*models.Car, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var cars []*models.Car
+
+	for _, car := range s.cars {
+		if (model != "" && car.Model != model) || (mark != "" && car.Mark != mark) ||
+			(ownerID != 0 && car.OwnerID != ownerID) {
+			continue
+		}
+
+		copyCar := *car
+		cars = append(cars, &copyCar)
+	}
+
+	slices.SortFunc(cars, func(a, b *models.Car) int {
+		byYear := 0
+
+		switch sortByYearType {
+		case byYearDESC:
+			byYear = cmp.Compare(b.Year, a.Year)
+		case byYearASC:
+			byYear = cmp.Compare(a.Year, b.Year)
+		}
+
+		if byYear != 0 {
+			return byYear
+		}
+
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	// postgres repository returns nil if no rows are selected
+	if offset >= uint64(len(cars)) || limit == 0 {
+		return nil, nil
+	}
+
+	cars = cars[offset:]
+	if limit < uint64(len(cars)) {
+		cars = cars[:limit]
+	}
+
+	return cars, nil
+}
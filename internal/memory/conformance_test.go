@@ -0,0 +1,18 @@
+package memory_test
+
+import (
+	"github.com/SanExpett/auto-catalog/internal/conformance"
+	"github.com/SanExpett/auto-catalog/internal/memory"
+	"testing"
+)
+
+func TestConformance(t *testing.T) {
+	conformance.RunConformance(t, func() conformance.Storage {
+		storage, err := memory.NewStorage()
+		if err != nil {
+			t.Fatalf("new storage: %v", err)
+		}
+
+		return storage
+	})
+}
@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	peoplerepo "github.com/SanExpett/auto-catalog/internal/people/repository"
+	"github.com/SanExpett/auto-catalog/pkg/models"
+	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
+)
+
+const maxLenPeopleField = 64
+
+func (s *Storage) AddPerson(ctx context.Context, prePeople *models.PrePeople) (*models.People, error) {
+	err := checkConstraints([]check{
+		{name: "max_len_name", violated: length(prePeople.Name) > maxLenPeopleField},
+		{name: "max_len_patronymic", violated: length(prePeople.Patronymic) > maxLenPeopleField},
+		{name: "max_len_surname", violated: length(prePeople.Surname) > maxLenPeopleField},
+		{name: "people_name_check", violated: prePeople.Name == ""},
+		{name: "people_surname_check", violated: prePeople.Surname == ""},
+	}, peoplerepo.PeopleConstraints)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastIDs.people++
+
+	people := &models.People{ID: s.lastIDs.people, Name: prePeople.Name, Surname: prePeople.Surname,
+		Patronymic: prePeople.Patronymic, CreatedAt: s.now()}
+	s.people[people.ID] = people
+
+	copyPeople := *people
+
+	return &copyPeople, nil
+}
+
+func (s *Storage) GetPerson(ctx context.Context, peopleID uint64) (*models.People, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	people, ok := s.people[peopleID]
+	if !ok {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, peoplerepo.ErrPeopleNotFound)
+	}
+
+	copyPeople := *people
+
+	return &copyPeople, nil
+}
+
+// DeletePerson deletes person with cars of that person and unlinks users from person.
+func (s *Storage) DeletePerson(ctx context.Context, personID uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.people[personID]; !ok {
+		return fmt.Errorf(myerrors.ErrTemplate, peoplerepo.ErrNoAffectedPeopleRows)
+	}
+
+	delete(s.people, personID)
+
+	for id, car := range s.cars {
+		if car.OwnerID == personID {
+			delete(s.cars, id)
+		}
+	}
+
+	for _, stored := range s.users {
+		if stored.user.PersonID != nil && *stored.user.PersonID == personID {
+			stored.user.PersonID = nil
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"github.com/SanExpett/auto-catalog/internal/server/repository"
+	userrepo "github.com/SanExpett/auto-catalog/internal/user/repository"
+	"github.com/SanExpett/auto-catalog/pkg/models"
+	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
+	"github.com/jackc/pgx/v5/pgconn"
+	"time"
+)
+
+const (
+	// lastSeenPrecision limits updates of last activity time to one per session in this period
+	lastSeenPrecision = time.Minute
+)
+
+func (s *Storage) AddSession(ctx context.Context, userID uint64, tokenHash []byte, expiresAt time.Time,
+) (*models.Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// postgres repository doesn't map violations of table session
+	if _, ok := s.users[userID]; !ok {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, &pgconn.PgError{ //nolint:exhaustruct
+			Code: repository.PgCodeForeignKeyViolation, ConstraintName: "session_user_id_fkey"})
+	}
+
+	if s.sessionByHash(tokenHash) != nil {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, &pgconn.PgError{ //nolint:exhaustruct
+			Code: repository.PgCodeUniqueViolation, ConstraintName: "session_token_hash_key"})
+	}
+
+	s.lastIDs.session++
+
+	now := s.now()
+	session := &models.Session{ID: s.lastIDs.session, UserID: userID, Token: "", CreatedAt: now, LastSeenAt: now,
+		ExpiresAt: expiresAt}
+	s.sessions[session.ID] = &storedSession{session: session, tokenHash: string(tokenHash)}
+
+	copySession := *session
+
+	return &copySession, nil
+}
+
+func (s *Storage) GetSessionUser(ctx context.Context, tokenHash []byte, activeSince time.Time,
+) (*models.Session, *models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stored := s.sessionByHash(tokenHash)
+	if stored == nil || s.sessionExpired(stored.session, activeSince) {
+		return nil, nil, fmt.Errorf(myerrors.ErrTemplate, userrepo.ErrSessionNotFound)
+	}
+
+	user, ok := s.users[stored.session.UserID]
+	if !ok {
+		return nil, nil, fmt.Errorf(myerrors.ErrTemplate, userrepo.ErrSessionNotFound)
+	}
+
+	copySession := *stored.session
+
+	return &copySession, copyUser(user.user), nil
+}
+
+// TouchSession records activity in session, time is updated not more often than lastSeenPrecision.
+func (s *Storage) TouchSession(ctx context.Context, sessionID uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.sessions[sessionID]
+	if !ok {
+		return nil
+	}
+
+	now := s.now()
+	if stored.session.LastSeenAt.Before(now.Add(-lastSeenPrecision)) {
+		stored.session.LastSeenAt = now
+	}
+
+	return nil
+}
+
+func (s *Storage) DeleteSession(ctx context.Context, tokenHash []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if stored := s.sessionByHash(tokenHash); stored != nil {
+		delete(s.sessions, stored.session.ID)
+	}
+
+	return nil
+}
+
+func (s *Storage) DeleteExpiredSessions(ctx context.Context, activeSince time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+
+	for id, stored := range s.sessions {
+		if s.sessionExpired(stored.session, activeSince) {
+			delete(s.sessions, id)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+// sessionExpired reports whether session has expired or has no activity since activeSince.
+func (s *Storage) sessionExpired(session *models.Session, activeSince time.Time) bool {
+	return !session.ExpiresAt.After(s.now()) || !session.LastSeenAt.After(activeSince)
+}
+
+func (s *Storage) sessionByHash(tokenHash []byte) *storedSession {
+	for _, stored := range s.sessions {
+		if stored.tokenHash == string(tokenHash) {
+			return stored
+		}
+	}
+
+	return nil
+}
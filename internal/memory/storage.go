@@ -0,0 +1,117 @@
+// Package memory holds storage which keeps all data in memory and behaves as postgres repositories do:
+// it returns the same errors, checks the same constraints of db/migrations and cascades deletes the same way.
+// Data is lost on restart, so it is meant for development and tests.
+package memory
+
+import (
+	"errors"
+	"fmt"
+	apikeyusecases "github.com/SanExpett/auto-catalog/internal/apikey/usecases"
+	carusecases "github.com/SanExpett/auto-catalog/internal/car/usecases"
+	peopleusecases "github.com/SanExpett/auto-catalog/internal/people/usecases"
+	"github.com/SanExpett/auto-catalog/internal/server/repository"
+	userusecases "github.com/SanExpett/auto-catalog/internal/user/usecases"
+	"github.com/SanExpett/auto-catalog/pkg/models"
+	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
+	"github.com/jackc/pgx/v5/pgconn"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	_ carusecases.ICarStorage       = (*Storage)(nil)
+	_ peopleusecases.IPeopleStorage = (*Storage)(nil)
+	_ userusecases.IUserStorage     = (*Storage)(nil)
+	_ userusecases.ISessionStorage  = (*Storage)(nil)
+	_ apikeyusecases.IAPIKeyStorage = (*Storage)(nil)
+)
+
+var (
+	ErrUnknownColumn = errors.New("column does not exist")
+	ErrInvalidValue  = errors.New("value has wrong type for column")
+)
+
+// check is CHECK constraint of table, violated is set if row breaks it.
+type check struct {
+	name     string
+	violated bool
+}
+
+// storedUser is user with hash of password.
+type storedUser struct {
+	user         *models.User
+	passwordHash string
+}
+
+// storedAPIKey is key with its hash.
+type storedAPIKey struct {
+	apiKey  *models.APIKey
+	keyHash string
+}
+
+// storedSession is session with hash of its token.
+type storedSession struct {
+	session   *models.Session
+	tokenHash string
+}
+
+// sequences are counters of ids, every table has its own one as in postgres.
+type sequences struct {
+	people  uint64
+	car     uint64
+	user    uint64
+	session uint64
+	apiKey  uint64
+}
+
+// Storage keeps people, cars, users, sessions and API keys. It is safe for concurrent use.
+type Storage struct {
+	mu sync.RWMutex
+
+	people   map[uint64]*models.People
+	cars     map[uint64]*models.Car
+	users    map[uint64]*storedUser
+	sessions map[uint64]*storedSession
+	apiKeys  map[uint64]*storedAPIKey
+	lastIDs  sequences
+
+	now func() time.Time
+}
+
+func NewStorage() (*Storage, error) {
+	return &Storage{
+		mu:       sync.RWMutex{},
+		people:   make(map[uint64]*models.People),
+		cars:     make(map[uint64]*models.Car),
+		users:    make(map[uint64]*storedUser),
+		sessions: make(map[uint64]*storedSession),
+		apiKeys:  make(map[uint64]*storedAPIKey),
+		lastIDs:  sequences{people: 0, car: 0, user: 0, session: 0, apiKey: 0},
+		now:      time.Now,
+	}, nil
+}
+
+// checkConstraints returns error of first violated check. Checks must be sorted by name, postgres
+// checks constraints of table in this order.
+func checkConstraints(checks []check, constraints map[string]repository.Constraint) error {
+	for _, check := range checks {
+		if check.violated {
+			return violation(repository.PgCodeCheckViolation, check.name, constraints)
+		}
+	}
+
+	return nil
+}
+
+// violation returns error which postgres repository returns when constraint name is violated.
+func violation(code string, name string, constraints map[string]repository.Constraint) error {
+	pgErr := &pgconn.PgError{Code: code, ConstraintName: name} //nolint:exhaustruct
+
+	return fmt.Errorf(myerrors.ErrTemplate, repository.MapPgError(pgErr, constraints))
+}
+
+// length counts characters as LENGTH of postgres does.
+func length(value string) int {
+	return utf8.RuneCountInString(value)
+}
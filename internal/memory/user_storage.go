@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"github.com/SanExpett/auto-catalog/internal/server/repository"
+	userrepo "github.com/SanExpett/auto-catalog/internal/user/repository"
+	"github.com/SanExpett/auto-catalog/pkg/models"
+	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
+)
+
+const maxLenEmail = 256
+
+func (s *Storage) AddUser(ctx context.Context, email string, passwordHash string) (*models.User, error) {
+	err := checkConstraints([]check{
+		{name: "max_len_email", violated: length(email) > maxLenEmail},
+		{name: "user_email_check", violated: email == ""},
+	}, userrepo.UserConstraints)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.userByEmail(email) != nil {
+		return nil, violation(repository.PgCodeUniqueViolation, "user_email_key", userrepo.UserConstraints)
+	}
+
+	s.lastIDs.user++
+
+	user := &models.User{ID: s.lastIDs.user, Email: email, PersonID: nil, CreatedAt: s.now()}
+	s.users[user.ID] = &storedUser{user: user, passwordHash: passwordHash}
+
+	return copyUser(user), nil
+}
+
+func (s *Storage) GetUserByEmail(ctx context.Context, email string) (*models.User, string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stored := s.userByEmail(email)
+	if stored == nil {
+		return nil, "", fmt.Errorf(myerrors.ErrTemplate, userrepo.ErrUserNotFound)
+	}
+
+	return copyUser(stored.user), stored.passwordHash, nil
+}
+
+func (s *Storage) LinkPerson(ctx context.Context, email string, personID *uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := s.userByEmail(email)
+	if stored == nil {
+		return fmt.Errorf(myerrors.ErrTemplate, userrepo.ErrUserNotFound)
+	}
+
+	if personID != nil {
+		for _, other := range s.users {
+			if other != stored && other.user.PersonID != nil && *other.user.PersonID == *personID {
+				return violation(repository.PgCodeUniqueViolation, "user_person_id_key", userrepo.UserConstraints)
+			}
+		}
+
+		if _, ok := s.people[*personID]; !ok {
+			return violation(repository.PgCodeForeignKeyViolation, "user_person_id_fkey", userrepo.UserConstraints)
+		}
+
+		linkedID := *personID
+		personID = &linkedID
+	}
+
+	stored.user.PersonID = personID
+
+	return nil
+}
+
+func (s *Storage) userByEmail(email string) *storedUser {
+	for _, stored := range s.users {
+		if stored.user.Email == email {
+			return stored
+		}
+	}
+
+	return nil
+}
+
+func copyUser(user *models.User) *models.User {
+	copied := *user
+
+	if user.PersonID != nil {
+		personID := *user.PersonID
+		copied.PersonID = &personID
+	}
+
+	return &copied
+}
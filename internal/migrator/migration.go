@@ -0,0 +1,107 @@
+package migrator
+
+import (
+	"errors"
+	"fmt"
+	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	suffixUp   = ".up.sql"
+	suffixDown = ".down.sql"
+)
+
+var (
+	ErrMigrationName       = errors.New("name of migration must be <version>_<title>.up.sql or .down.sql")
+	ErrMigrationDuplicated = errors.New("migration is duplicated")
+	ErrMigrationNoUp       = errors.New("migration has no up file")
+)
+
+// Migration is pair of sql scripts named <version>_<title>.up.sql and <version>_<title>.down.sql.
+type Migration struct {
+	Version uint64
+	Title   string
+	Up      string
+	Down    string
+}
+
+// Load reads migrations from dir of fsys sorted by version.
+func Load(fsys fs.FS, dir string) ([]*Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	byVersion := make(map[uint64]*Migration)
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		name := entry.Name()
+
+		base, isUp := strings.CutSuffix(name, suffixUp)
+		if !isUp {
+			var isDown bool
+
+			base, isDown = strings.CutSuffix(name, suffixDown)
+			if !isDown {
+				return nil, fmt.Errorf("%w: %s", ErrMigrationName, name)
+			}
+		}
+
+		versionStr, title, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrMigrationName, name)
+		}
+
+		version, err := strconv.ParseUint(versionStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrMigrationName, name)
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Title: title, Up: "", Down: ""}
+			byVersion[version] = migration
+		}
+
+		script := &migration.Down
+		if isUp {
+			script = &migration.Up
+		}
+
+		if migration.Title != title || *script != "" {
+			return nil, fmt.Errorf("%w: %s", ErrMigrationDuplicated, name)
+		}
+
+		*script = string(content)
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("%w: %d_%s", ErrMigrationNoUp, migration.Version, migration.Title)
+		}
+
+		migrations = append(migrations, migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
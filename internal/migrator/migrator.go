@@ -0,0 +1,298 @@
+// Package migrator applies sql migrations of db/migrations embedded into binary. State is kept in
+// schema_migrations table of the same format as golang-migrate uses, so both tools can be used.
+package migrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/SanExpett/auto-catalog/db"
+	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
+	"github.com/SanExpett/auto-catalog/pkg/my_logger"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// lockKey is key of advisory lock taken while migrating, so replicas started together don't race.
+	lockKey int64 = 2_024_040_306
+
+	pgCodeUndefinedTable = "42P01"
+)
+
+var (
+	ErrDirtySchema     = errors.New("last migration failed halfway, schema is dirty and must be fixed manually")
+	ErrSchemaNewer     = errors.New("schema is newer than migrations known by application")
+	ErrUnknownVersion  = errors.New("version of schema isn't known by application")
+	ErrMigrationNoDown = errors.New("migration has no down file")
+)
+
+// Status describes state of schema relative to known migrations. Version is 0 if nothing is applied.
+type Status struct {
+	Version uint64
+	Dirty   bool
+	Latest  uint64
+	Pending []*Migration
+}
+
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []*Migration
+}
+
+func NewMigrator(pool *pgxpool.Pool, migrations []*Migration) *Migrator {
+	return &Migrator{pool: pool, migrations: migrations}
+}
+
+// NewEmbeddedMigrator returns Migrator of migrations embedded from db/migrations.
+func NewEmbeddedMigrator(pool *pgxpool.Pool) (*Migrator, error) {
+	migrations, err := Load(db.Migrations, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMigrator(pool, migrations), nil
+}
+
+// Latest returns version of the newest known migration.
+func (m *Migrator) Latest() uint64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// index returns position of migration with version, -1 for version 0 and false for unknown version.
+func (m *Migrator) index(version uint64) (int, bool) {
+	if version == 0 {
+		return -1, true
+	}
+
+	for i, migration := range m.migrations {
+		if migration.Version == version {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+// Status reads state of schema without changing anything.
+func (m *Migrator) Status(ctx context.Context) (*Status, error) {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+	defer conn.Release()
+
+	version, dirty, err := readVersion(ctx, conn.Conn())
+	if err != nil {
+		return nil, err
+	}
+
+	status := &Status{Version: version, Dirty: dirty, Latest: m.Latest(), Pending: nil}
+
+	for _, migration := range m.migrations {
+		if migration.Version > version {
+			status.Pending = append(status.Pending, migration)
+		}
+	}
+
+	return status, nil
+}
+
+// Check returns error if schema is dirty or newer than known migrations, application must not start then.
+func (m *Migrator) Check(ctx context.Context) (*Status, error) {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return status, m.check(status.Version, status.Dirty)
+}
+
+func (m *Migrator) check(version uint64, dirty bool) error {
+	if dirty {
+		return fmt.Errorf("%w: version=%d", ErrDirtySchema, version)
+	}
+
+	if version > m.Latest() {
+		return fmt.Errorf("%w: version=%d, latest=%d", ErrSchemaNewer, version, m.Latest())
+	}
+
+	if _, ok := m.index(version); !ok {
+		return fmt.Errorf("%w: version=%d", ErrUnknownVersion, version)
+	}
+
+	return nil
+}
+
+// Up applies all pending migrations and returns count of applied ones.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	applied := 0
+
+	err := m.withLock(ctx, func(conn *pgx.Conn) error {
+		version, dirty, err := readVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		if err := m.check(version, dirty); err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if migration.Version <= version {
+				continue
+			}
+
+			if err := apply(ctx, conn, migration.Version, migration.Up); err != nil {
+				return fmt.Errorf("migration %d_%s up: %w", migration.Version, migration.Title, err)
+			}
+
+			my_logger.FromContext(ctx).Infof("applied migration %d_%s", migration.Version, migration.Title)
+
+			applied++
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down reverts steps last applied migrations and returns count of reverted ones.
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	reverted := 0
+
+	err := m.withLock(ctx, func(conn *pgx.Conn) error {
+		version, dirty, err := readVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		if err := m.check(version, dirty); err != nil {
+			return err
+		}
+
+		current, _ := m.index(version)
+
+		for ; reverted < steps && current >= 0; current-- {
+			migration := m.migrations[current]
+			if migration.Down == "" {
+				return fmt.Errorf("%w: %d_%s", ErrMigrationNoDown, migration.Version, migration.Title)
+			}
+
+			var previous uint64
+			if current > 0 {
+				previous = m.migrations[current-1].Version
+			}
+
+			if err := apply(ctx, conn, previous, migration.Down); err != nil {
+				return fmt.Errorf("migration %d_%s down: %w", migration.Version, migration.Title, err)
+			}
+
+			my_logger.FromContext(ctx).Infof("reverted migration %d_%s", migration.Version, migration.Title)
+
+			reverted++
+		}
+
+		return nil
+	})
+
+	return reverted, err
+}
+
+// withLock runs fn on connection holding advisory lock of migrations.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgx.Conn) error) error {
+	poolConn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+	defer poolConn.Release()
+
+	conn := poolConn.Conn()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, lockKey); err != nil {
+		return fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	defer func() {
+		// lock must be released even if ctx is canceled, otherwise it stays with connection in pool
+		if _, err := conn.Exec(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, lockKey); err != nil {
+			my_logger.FromContext(ctx).Errorf("in withLock: unlock: %+v", err)
+		}
+	}()
+
+	SQLCreateSchemaMigrations := `CREATE TABLE IF NOT EXISTS public."schema_migrations"
+		(version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL);`
+
+	if _, err := conn.Exec(ctx, SQLCreateSchemaMigrations); err != nil {
+		return fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	return fn(conn)
+}
+
+// apply marks schema dirty with version, runs script and clears dirty mark. If script fails
+// schema stays dirty, as it isn't known which part of script was applied.
+func apply(ctx context.Context, conn *pgx.Conn, version uint64, script string) error {
+	if err := setVersion(ctx, conn, version, true); err != nil {
+		return err
+	}
+
+	// without arguments script is sent by simple protocol, so it may contain several statements
+	if _, err := conn.Exec(ctx, script); err != nil {
+		return fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	return setVersion(ctx, conn, version, false)
+}
+
+func readVersion(ctx context.Context, conn *pgx.Conn) (uint64, bool, error) {
+	SQLSelectVersion := `SELECT version, dirty FROM public."schema_migrations" LIMIT 1;`
+
+	var (
+		version uint64
+		dirty   bool
+	)
+
+	err := conn.QueryRow(ctx, SQLSelectVersion).Scan(&version, &dirty)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.Is(err, pgx.ErrNoRows) || (errors.As(err, &pgErr) && pgErr.Code == pgCodeUndefinedTable) {
+			return 0, false, nil
+		}
+
+		return 0, false, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	return version, dirty, nil
+}
+
+// setVersion replaces state of schema, version 0 means that nothing is applied.
+func setVersion(ctx context.Context, conn *pgx.Conn, version uint64, dirty bool) error {
+	err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM public."schema_migrations";`); err != nil {
+			return fmt.Errorf(myerrors.ErrTemplate, err)
+		}
+
+		if version == 0 && !dirty {
+			return nil
+		}
+
+		SQLInsertVersion := `INSERT INTO public."schema_migrations" (version, dirty) VALUES ($1, $2);`
+		if _, err := tx.Exec(ctx, SQLInsertVersion, version, dirty); err != nil {
+			return fmt.Errorf(myerrors.ErrTemplate, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	return nil
+}
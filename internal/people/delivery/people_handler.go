@@ -10,7 +10,6 @@ import (
 	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
 	"github.com/SanExpett/auto-catalog/pkg/my_logger"
 	"github.com/SanExpett/auto-catalog/pkg/utils"
-	"go.uber.org/zap"
 	"io"
 	"net/http"
 )
@@ -25,18 +24,11 @@ type IPeopleService interface {
 
 type PeopleHandler struct {
 	service IPeopleService
-	logger  *zap.SugaredLogger
 }
 
 func NewPeopleHandler(PeopleService IPeopleService) (*PeopleHandler, error) {
-	logger, err := my_logger.Get()
-	if err != nil {
-		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
-	}
-
 	return &PeopleHandler{
 		service: PeopleService,
-		logger:  logger,
 	}, nil
 }
 
@@ -56,25 +48,24 @@ func NewPeopleHandler(PeopleService IPeopleService) (*PeopleHandler, error) {
 //	@Failure    405  {string} string
 //	@Failure    500  {string} string
 //	@Failure    222  {object} delivery.ErrorResponse "Error"
-//	@Router      /people/add [post]
+//	@Failure    401  {object} delivery.ErrorResponse "Unauthorized"
+//	@Failure    403  {object} delivery.ErrorResponse "Forbidden"
+//	@Failure    429  {object} delivery.ErrorResponse "Too Many Requests"
+//	@Failure    503  {object} delivery.ErrorResponse "Service Unavailable"
+//	@Router      /v1/people/add [post]
 func (p *PeopleHandler) AddPeopleHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, `Method not allowed`, http.StatusMethodNotAllowed)
-
-		return
-	}
-
 	ctx := r.Context()
+	logger := my_logger.FromContext(ctx)
 
 	person, err := p.service.AddPerson(ctx, r.Body)
 	if err != nil {
-		delivery.HandleErr(w, p.logger, err)
+		delivery.HandleErr(w, r, logger, err)
 
 		return
 	}
 
-	delivery.SendOkResponse(w, p.logger, NewPeopleResponse(delivery.StatusResponseSuccessful, person))
-	p.logger.Infof("in AddPeopleHandler: add people: %+v", person)
+	delivery.SendOkResponse(w, r, logger, NewPeopleResponse(delivery.StatusResponseSuccessful, person))
+	logger.Infof("in AddPeopleHandler: add people: %+v", person)
 }
 
 // GetPeopleHandler godoc
@@ -85,36 +76,38 @@ func (p *PeopleHandler) AddPeopleHandler(w http.ResponseWriter, r *http.Request)
 //	@Accept      json
 //	@Produce    json
 //	@Param      id  query uint64 true  "People id"
+//	@Param      If-None-Match  header string false  "ETag of cached body, 304 is sent while it is current"
 //	@Success    200  {object} PeopleResponse
+//	@Success    304  "Not Modified"
+//	@Header     200,304  {string} ETag "strong validator of body"
 //	@Failure    405  {string} string
 //	@Failure    500  {string} string
 //	@Failure    222  {object} delivery.ErrorResponse "Error"
-//	@Router      /people/get [get]
+//	@Failure    401  {object} delivery.ErrorResponse "Unauthorized"
+//	@Failure    403  {object} delivery.ErrorResponse "Forbidden"
+//	@Failure    429  {object} delivery.ErrorResponse "Too Many Requests"
+//	@Failure    503  {object} delivery.ErrorResponse "Service Unavailable"
+//	@Router      /v1/people/get [get]
 func (p *PeopleHandler) GetPeopleHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, `Method not allowed`, http.StatusMethodNotAllowed)
-
-		return
-	}
-
 	ctx := r.Context()
+	logger := my_logger.FromContext(ctx)
 
 	peopleID, err := utils.ParseUint64FromRequest(r, "id")
 	if err != nil {
-		delivery.HandleErr(w, p.logger, err)
+		delivery.HandleErr(w, r, logger, err)
 
 		return
 	}
 
 	people, err := p.service.GetPerson(ctx, peopleID)
 	if err != nil {
-		delivery.HandleErr(w, p.logger, err)
+		delivery.HandleErr(w, r, logger, err)
 
 		return
 	}
 
-	delivery.SendOkResponse(w, p.logger, NewPeopleResponse(delivery.StatusResponseSuccessful, people))
-	p.logger.Infof("in GetPeopleHandler: get People: %+v", people)
+	delivery.SendOkResponse(w, r, logger, NewPeopleResponse(delivery.StatusResponseSuccessful, people))
+	logger.Infof("in GetPeopleHandler: get People: %+v", people)
 }
 
 // DeletePeopleHandler godoc
@@ -130,31 +123,146 @@ func (p *PeopleHandler) GetPeopleHandler(w http.ResponseWriter, r *http.Request)
 //	@Failure    405  {string} string
 //	@Failure    500  {string} string
 //	@Failure    222  {object} delivery.ErrorResponse "Error"
-//	@Router      /people/delete [delete]
+//	@Failure    401  {object} delivery.ErrorResponse "Unauthorized"
+//	@Failure    403  {object} delivery.ErrorResponse "Forbidden"
+//	@Failure    429  {object} delivery.ErrorResponse "Too Many Requests"
+//	@Failure    503  {object} delivery.ErrorResponse "Service Unavailable"
+//	@Router      /v1/people/delete [delete]
 func (p *PeopleHandler) DeletePeopleHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodDelete {
-		http.Error(w, `Method not allowed`, http.StatusMethodNotAllowed)
+	ctx := r.Context()
+	logger := my_logger.FromContext(ctx)
+
+	personID, err := utils.ParseUint64FromRequest(r, "id")
+	if err != nil {
+		delivery.HandleErr(w, r, logger, err)
 
 		return
 	}
 
+	err = p.service.DeletePerson(ctx, personID)
+	if err != nil {
+		delivery.HandleErr(w, r, logger, err)
+
+		return
+	}
+
+	delivery.SendOkResponse(w, r, logger,
+		delivery.NewResponse(delivery.StatusResponseSuccessful,
+			myerrors.Message(delivery.LangFromContext(ctx), ResponseSuccessfulDeletePeople)))
+	logger.Infof("in DeletePeopleHandler: delete People id=%d", personID)
+}
+
+// AddPeopleV2Handler godoc
+//
+//	@Summary    add people
+//	@Description  add People by data
+//	@Tags People
+//	@Accept      json
+//	@Produce    json,application/problem+json
+//	@Param      People  body models.PrePeople true  "People data for adding"
+//	@Success    201  {object} models.People
+//	@Header     201  {string} Location "path of added People"
+//	@Failure    400  {object} delivery.Problem "Bad Request"
+//	@Failure    401  {object} delivery.Problem "Unauthorized"
+//	@Failure    403  {object} delivery.Problem "Forbidden"
+//	@Failure    405  {object} delivery.Problem "Method Not Allowed"
+//	@Failure    422  {object} delivery.Problem "Unprocessable Entity"
+//	@Failure    429  {object} delivery.Problem "Too Many Requests"
+//	@Failure    500  {object} delivery.Problem "Internal Server Error"
+//	@Failure    503  {object} delivery.Problem "Service Unavailable"
+//	@Router      /v2/people [post]
+func (p *PeopleHandler) AddPeopleV2Handler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := my_logger.FromContext(ctx)
+
+	person, err := p.service.AddPerson(ctx, r.Body)
+	if err != nil {
+		delivery.HandleErr(w, r, logger, err)
+
+		return
+	}
+
+	delivery.SendCreatedResponse(w, r, logger, fmt.Sprintf("/api/v2/people/%d", person.ID), person)
+	logger.Infof("in AddPeopleV2Handler: add people: %+v", person)
+}
+
+// GetPeopleV2Handler godoc
+//
+//	@Summary    get People
+//	@Description  get People by id
+//	@Tags People
+//	@Produce    json,application/problem+json
+//	@Param      id  path uint64 true  "People id"
+//	@Param      If-None-Match  header string false  "ETag of cached body, 304 is sent while it is current"
+//	@Success    200  {object} models.People
+//	@Success    304  "Not Modified"
+//	@Header     200,304  {string} ETag "strong validator of body"
+//	@Failure    400  {object} delivery.Problem "Bad Request"
+//	@Failure    401  {object} delivery.Problem "Unauthorized"
+//	@Failure    403  {object} delivery.Problem "Forbidden"
+//	@Failure    404  {object} delivery.Problem "Not Found"
+//	@Failure    405  {object} delivery.Problem "Method Not Allowed"
+//	@Failure    429  {object} delivery.Problem "Too Many Requests"
+//	@Failure    500  {object} delivery.Problem "Internal Server Error"
+//	@Failure    503  {object} delivery.Problem "Service Unavailable"
+//	@Router      /v2/people/{id} [get]
+func (p *PeopleHandler) GetPeopleV2Handler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := my_logger.FromContext(ctx)
+
+	personID, err := utils.ParseUint64FromRequest(r, "id")
+	if err != nil {
+		delivery.HandleErr(w, r, logger, err)
+
+		return
+	}
+
+	person, err := p.service.GetPerson(ctx, personID)
+	if err != nil {
+		delivery.HandleErr(w, r, logger, err)
+
+		return
+	}
+
+	delivery.SendOkResponse(w, r, logger, person)
+	logger.Infof("in GetPeopleV2Handler: get People: %+v", person)
+}
+
+// DeletePeopleV2Handler godoc
+//
+//	@Summary     delete People
+//	@Description  delete People by id together with their cars. Recovery will be impossible
+//	@Tags People
+//	@Produce    json,application/problem+json
+//	@Param      id  path uint64 true  "People id"
+//	@Success    204  "No Content"
+//	@Failure    400  {object} delivery.Problem "Bad Request"
+//	@Failure    401  {object} delivery.Problem "Unauthorized"
+//	@Failure    403  {object} delivery.Problem "Forbidden"
+//	@Failure    404  {object} delivery.Problem "Not Found"
+//	@Failure    405  {object} delivery.Problem "Method Not Allowed"
+//	@Failure    429  {object} delivery.Problem "Too Many Requests"
+//	@Failure    500  {object} delivery.Problem "Internal Server Error"
+//	@Failure    503  {object} delivery.Problem "Service Unavailable"
+//	@Router      /v2/people/{id} [delete]
+func (p *PeopleHandler) DeletePeopleV2Handler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	logger := my_logger.FromContext(ctx)
 
 	personID, err := utils.ParseUint64FromRequest(r, "id")
 	if err != nil {
-		delivery.HandleErr(w, p.logger, err)
+		delivery.HandleErr(w, r, logger, err)
 
 		return
 	}
 
 	err = p.service.DeletePerson(ctx, personID)
 	if err != nil {
-		delivery.HandleErr(w, p.logger, err)
+		delivery.HandleErr(w, r, logger, err)
 
 		return
 	}
 
-	delivery.SendOkResponse(w, p.logger,
-		delivery.NewResponse(delivery.StatusResponseSuccessful, ResponseSuccessfulDeletePeople))
-	p.logger.Infof("in DeletePeopleHandler: delete People id=%d", personID)
+	delivery.SendNoContent(w)
+	logger.Infof("in DeletePeopleV2Handler: delete People id=%d", personID)
 }
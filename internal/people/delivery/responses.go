@@ -3,7 +3,7 @@ package delivery
 import "github.com/SanExpett/auto-catalog/pkg/models"
 
 const (
-	ResponseSuccessfulDeletePeople = "Человек успешно удален"
+	ResponseSuccessfulDeletePeople = "person_deleted"
 )
 
 type PeopleResponse struct {
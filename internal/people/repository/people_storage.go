@@ -5,37 +5,37 @@ import (
 	"errors"
 	"fmt"
 	"github.com/SanExpett/auto-catalog/internal/server/repository"
+	"github.com/SanExpett/auto-catalog/pkg/metrics"
 	"github.com/SanExpett/auto-catalog/pkg/models"
 	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
 	"github.com/SanExpett/auto-catalog/pkg/my_logger"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
-	"go.uber.org/zap"
 	"time"
 )
 
 var (
-	ErrPeopleNotFound       = myerrors.NewError("Этот человек не найден")
-	ErrNoAffectedPeopleRows = myerrors.NewError("Не получилось обновить данные человека")
+	ErrPeopleNotFound       = myerrors.NewNotFoundError("person_not_found")
+	ErrNoAffectedPeopleRows = myerrors.NewNotFoundError("person_not_updated")
 
 	NameSeqPeople = pgx.Identifier{"public", "people_id_seq"} //nolint:gochecknoglobals
+
+	// PeopleConstraints are constraints of table people, memory storage checks them too
+	PeopleConstraints = map[string]repository.Constraint{ //nolint:gochecknoglobals
+		"people_name_check":    {Field: "name", Key: "name_empty"},
+		"max_len_name":         {Field: "name", Key: "name_too_long"},
+		"people_surname_check": {Field: "surname", Key: "surname_empty"},
+		"max_len_surname":      {Field: "surname", Key: "surname_too_long"},
+		"max_len_patronymic":   {Field: "patronymic", Key: "patronymic_too_long"},
+	}
 )
 
 type PeopleStorage struct {
-	pool   *pgxpool.Pool
-	logger *zap.SugaredLogger
+	pool *pgxpool.Pool
 }
 
 func NewPeopleStorage(pool *pgxpool.Pool) (*PeopleStorage, error) {
-	logger, err := my_logger.Get()
-	if err != nil {
-		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
-	}
-
-	return &PeopleStorage{
-		pool:   pool,
-		logger: logger,
-	}, nil
+	return &PeopleStorage{pool: pool}, nil
 }
 
 func (p *PeopleStorage) selectCreatedAtByPeopleID(ctx context.Context, tx pgx.Tx, peopleID uint64,
@@ -50,7 +50,7 @@ func (p *PeopleStorage) selectCreatedAtByPeopleID(ctx context.Context, tx pgx.Tx
 			return time.Time{}, fmt.Errorf(myerrors.ErrTemplate, ErrPeopleNotFound)
 		}
 
-		p.logger.Errorf("error with PeopleId=%d: %+v", peopleID, err)
+		my_logger.FromContext(ctx).Errorf("error with PeopleId=%d: %+v", peopleID, err)
 
 		return time.Time{}, fmt.Errorf(myerrors.ErrTemplate, err)
 	}
@@ -63,15 +63,17 @@ func (p *PeopleStorage) insertPeople(ctx context.Context, tx pgx.Tx, prePeople *
 	_, err := tx.Exec(ctx, SQLInsertPeople, prePeople.Name, prePeople.Surname, prePeople.Patronymic)
 
 	if err != nil {
-		p.logger.Errorln(err)
+		my_logger.FromContext(ctx).Errorln(err)
 
-		return fmt.Errorf(myerrors.ErrTemplate, err)
+		return fmt.Errorf(myerrors.ErrTemplate, repository.MapPgError(err, PeopleConstraints))
 	}
 
 	return nil
 }
 
 func (p *PeopleStorage) AddPerson(ctx context.Context, prePeople *models.PrePeople) (*models.People, error) {
+	defer metrics.ObserveQuery("people", "AddPerson", time.Now())
+
 	people := &models.People{Name: prePeople.Name, Surname: prePeople.Surname, Patronymic: prePeople.Patronymic}
 
 	err := pgx.BeginFunc(ctx, p.pool, func(tx pgx.Tx) error {
@@ -97,7 +99,7 @@ func (p *PeopleStorage) AddPerson(ctx context.Context, prePeople *models.PrePeop
 		return err
 	})
 	if err != nil {
-		p.logger.Errorln(err)
+		my_logger.FromContext(ctx).Errorln(err)
 
 		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
 	}
@@ -116,7 +118,7 @@ func (p *PeopleStorage) selectPeopleByID(ctx context.Context, tx pgx.Tx, peopleI
 			return nil, fmt.Errorf(myerrors.ErrTemplate, ErrPeopleNotFound)
 		}
 
-		p.logger.Errorf("error with PeopleId=%d: %+v", peopleID, err)
+		my_logger.FromContext(ctx).Errorf("error with PeopleId=%d: %+v", peopleID, err)
 
 		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
 	}
@@ -125,6 +127,8 @@ func (p *PeopleStorage) selectPeopleByID(ctx context.Context, tx pgx.Tx, peopleI
 }
 
 func (p *PeopleStorage) GetPerson(ctx context.Context, peopleID uint64) (*models.People, error) {
+	defer metrics.ObserveQuery("people", "GetPerson", time.Now())
+
 	var people *models.People
 
 	err := pgx.BeginFunc(ctx, p.pool, func(tx pgx.Tx) error {
@@ -149,7 +153,7 @@ func (p *PeopleStorage) deletePerson(ctx context.Context, tx pgx.Tx, personID ui
 
 	result, err := tx.Exec(ctx, SQLDeletePeople, personID)
 	if err != nil {
-		p.logger.Errorln(err)
+		my_logger.FromContext(ctx).Errorln(err)
 
 		return fmt.Errorf(myerrors.ErrTemplate, err)
 	}
@@ -163,6 +167,8 @@ func (p *PeopleStorage) deletePerson(ctx context.Context, tx pgx.Tx, personID ui
 }
 
 func (p *PeopleStorage) DeletePerson(ctx context.Context, personID uint64) error {
+	defer metrics.ObserveQuery("people", "DeletePerson", time.Now())
+
 	err := pgx.BeginFunc(ctx, p.pool, func(tx pgx.Tx) error {
 		err := p.deletePerson(ctx, tx, personID)
 		if err != nil {
@@ -172,7 +178,7 @@ func (p *PeopleStorage) DeletePerson(ctx context.Context, personID uint64) error
 		return nil
 	})
 	if err != nil {
-		p.logger.Errorln(err)
+		my_logger.FromContext(ctx).Errorln(err)
 
 		return fmt.Errorf(myerrors.ErrTemplate, err)
 	}
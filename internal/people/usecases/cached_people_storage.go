@@ -0,0 +1,67 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"github.com/SanExpett/auto-catalog/pkg/cache"
+	"github.com/SanExpett/auto-catalog/pkg/models"
+	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
+)
+
+var _ IPeopleStorage = (*CachedPeopleStorage)(nil)
+
+// CachedPeopleStorage reads people by id through cache, writes invalidate people they change. Cars of
+// deleted person are deleted by storage too, so they are invalidated in cache of cars.
+type CachedPeopleStorage struct {
+	storage IPeopleStorage
+	people  *cache.LRU[uint64, models.People]
+	cars    *cache.LRU[uint64, models.Car]
+}
+
+func NewCachedPeopleStorage(storage IPeopleStorage, people *cache.LRU[uint64, models.People],
+	cars *cache.LRU[uint64, models.Car],
+) (*CachedPeopleStorage, error) {
+	return &CachedPeopleStorage{storage: storage, people: people, cars: cars}, nil
+}
+
+// AddPerson invalidates nothing, new person is not cached yet.
+func (c *CachedPeopleStorage) AddPerson(ctx context.Context, prePeople *models.PrePeople) (*models.People, error) {
+	people, err := c.storage.AddPerson(ctx, prePeople)
+	if err != nil {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	return people, nil
+}
+
+// GetPerson returns copy of cached person, callers may change it.
+func (c *CachedPeopleStorage) GetPerson(ctx context.Context, peopleID uint64) (*models.People, error) {
+	people, err := c.people.GetOrLoad(ctx, peopleID, func(ctx context.Context) (models.People, error) {
+		people, err := c.storage.GetPerson(ctx, peopleID)
+		if err != nil {
+			return models.People{}, err //nolint:exhaustruct
+		}
+
+		return *people, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	return &people, nil
+}
+
+func (c *CachedPeopleStorage) DeletePerson(ctx context.Context, personID uint64) error {
+	defer func() {
+		c.people.Delete(personID)
+		c.cars.DeleteFunc(func(_ uint64, car models.Car) bool {
+			return car.OwnerID == personID
+		})
+	}()
+
+	if err := c.storage.DeletePerson(ctx, personID); err != nil {
+		return fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	return nil
+}
@@ -4,10 +4,9 @@ import (
 	"context"
 	"fmt"
 	peoplerepo "github.com/SanExpett/auto-catalog/internal/people/repository"
+	"github.com/SanExpett/auto-catalog/pkg/access"
 	"github.com/SanExpett/auto-catalog/pkg/models"
 	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
-	"github.com/SanExpett/auto-catalog/pkg/my_logger"
-	"go.uber.org/zap"
 	"io"
 )
 
@@ -21,20 +20,18 @@ type IPeopleStorage interface {
 
 type PeopleService struct {
 	storage IPeopleStorage
-	logger  *zap.SugaredLogger
 }
 
 func NewPeopleService(peopleStorage IPeopleStorage) (*PeopleService, error) {
-	logger, err := my_logger.Get()
-	if err != nil {
-		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
-	}
-
-	return &PeopleService{storage: peopleStorage, logger: logger}, nil
+	return &PeopleService{storage: peopleStorage}, nil
 }
 
 func (p *PeopleService) AddPerson(ctx context.Context, r io.Reader) (*models.People, error) {
-	prePeople, err := ValidatePrePeople(r)
+	if _, isOwner := access.OwnerFromContext(ctx); isOwner {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, access.ErrOwnerForbidden)
+	}
+
+	prePeople, err := ValidatePrePeople(ctx, r)
 	if err != nil {
 		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
 	}
@@ -47,7 +44,12 @@ func (p *PeopleService) AddPerson(ctx context.Context, r io.Reader) (*models.Peo
 	return people, nil
 }
 
+// GetPerson returns to owner only own person, others are not found.
 func (p *PeopleService) GetPerson(ctx context.Context, peopleID uint64) (*models.People, error) {
+	if personID, isOwner := access.OwnerFromContext(ctx); isOwner && personID != peopleID {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, peoplerepo.ErrPeopleNotFound)
+	}
+
 	people, err := p.storage.GetPerson(ctx, peopleID)
 	if err != nil {
 		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
@@ -59,6 +61,14 @@ func (p *PeopleService) GetPerson(ctx context.Context, peopleID uint64) (*models
 }
 
 func (p *PeopleService) DeletePerson(ctx context.Context, personID uint64) error {
+	if ownPersonID, isOwner := access.OwnerFromContext(ctx); isOwner {
+		if ownPersonID != personID {
+			return fmt.Errorf(myerrors.ErrTemplate, peoplerepo.ErrPeopleNotFound)
+		}
+
+		return fmt.Errorf(myerrors.ErrTemplate, access.ErrOwnerForbidden)
+	}
+
 	err := p.storage.DeletePerson(ctx, personID)
 	if err != nil {
 		return fmt.Errorf(myerrors.ErrTemplate, err)
@@ -1,40 +1,38 @@
 package usecases
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/SanExpett/auto-catalog/pkg/models"
 	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
 	"github.com/SanExpett/auto-catalog/pkg/my_logger"
-	"github.com/asaskevich/govalidator"
+	"github.com/SanExpett/auto-catalog/pkg/utils"
 	"io"
 )
 
 var (
-	ErrDecodePrePeople = myerrors.NewError("Некорректный json человека")
+	ErrDecodePrePeople = myerrors.NewError("person_decode")
 )
 
-func ValidatePrePeople(r io.Reader) (*models.PrePeople, error) {
-	logger, err := my_logger.Get()
-	if err != nil {
-		return nil, err
-	}
-
+func ValidatePrePeople(ctx context.Context, r io.Reader) (*models.PrePeople, error) {
 	decoder := json.NewDecoder(r)
 	prePeople := &models.PrePeople{}
 	if err := decoder.Decode(prePeople); err != nil {
-		logger.Errorln(err)
+		my_logger.FromContext(ctx).Errorln(err)
+
+		if fieldError, ok := utils.FieldErrorFromJSON(err); ok {
+			return nil, myerrors.NewValidationErrors([]myerrors.FieldError{fieldError})
+		}
 
 		return nil, fmt.Errorf(myerrors.ErrTemplate, ErrDecodePrePeople)
 	}
 
 	prePeople.Trim()
 
-	_, err = govalidator.ValidateStruct(prePeople)
-	if err != nil {
-		logger.Errorln(err)
-
-		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	fieldErrors := utils.ValidateStruct(prePeople, false)
+	if len(fieldErrors) != 0 {
+		return nil, myerrors.NewValidationErrors(fieldErrors)
 	}
 
 	return prePeople, nil
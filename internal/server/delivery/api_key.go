@@ -0,0 +1,19 @@
+package delivery
+
+import (
+	"context"
+	"github.com/SanExpett/auto-catalog/pkg/models"
+)
+
+type apiKeyKey struct{}
+
+func WithAPIKey(ctx context.Context, apiKey *models.APIKey) context.Context {
+	return context.WithValue(ctx, apiKeyKey{}, apiKey)
+}
+
+// APIKeyFromContext returns key which authenticated request or nil.
+func APIKeyFromContext(ctx context.Context) *models.APIKey {
+	apiKey, _ := ctx.Value(apiKeyKey{}).(*models.APIKey)
+
+	return apiKey
+}
@@ -14,14 +14,24 @@ const (
 	StatusResponseSuccessful      = 200
 	StatusRedirectAfterSuccessful = 303
 	StatusErrBadRequest           = 400
+	StatusErrNotFound             = 404
+	StatusErrConflict             = 409
+	StatusErrUnprocessableEntity  = 422
 	StatusErrInternalServer       = 500
 )
 
 const (
-	ErrInternalServer = "Ошибка на сервере"
+	ContentTypeJSON    = "application/json"
+	ContentTypeProblem = "application/problem+json"
+
+	cacheControlNoStore = "no-store"
 )
 
-var ErrCookieNotPresented = myerrors.NewError("Должна быть выставлена cookie, а её нет")
+var (
+	ErrInternalServer     = myerrors.NewInternalError("internal_server_error")
+	ErrCookieNotPresented = myerrors.NewError("cookie_not_presented")
+	ErrUnauthorized       = myerrors.NewUnauthorizedError("unauthorized")
+)
 
 const (
 	CookieAuthName = "access_token"
@@ -57,7 +67,9 @@ func NewResponseID(ID uint64) *ResponseID {
 }
 
 type ResponseBodyError struct {
-	Error string `json:"error"`
+	Code   string                `json:"code"`
+	Error  string                `json:"error"`
+	Fields []myerrors.FieldError `json:"fields,omitempty"`
 }
 
 type ErrorResponse struct {
@@ -65,37 +77,102 @@ type ErrorResponse struct {
 	Body   ResponseBodyError `json:"body"`
 }
 
-func NewErrResponse(status int, err string) *ErrorResponse {
+func NewErrResponse(status int, code string, err string, fieldErrors ...myerrors.FieldError) *ErrorResponse {
 	return &ErrorResponse{
 		Status: status,
-		Body:   ResponseBodyError{Error: err},
+		Body:   ResponseBodyError{Code: code, Error: err, Fields: fieldErrors},
 	}
 }
 
-func sendResponse(w http.ResponseWriter, logger *zap.SugaredLogger, response any) {
+// sendResponse writes response as json with status. Successful responses to GET and HEAD which may be cached
+// get strong ETag of body, they are sent as 304 without body if client already has the same body.
+func sendResponse(w http.ResponseWriter, r *http.Request, logger *zap.SugaredLogger, status int, response any) {
 	responseSend, err := json.Marshal(response)
 	if err != nil {
 		logger.Errorf("in sendResponse: %+v\n", err)
-		http.Error(w, ErrInternalServer, http.StatusInternalServerError)
+		http.Error(w, ErrInternalServer.Error(), http.StatusInternalServerError)
 
 		return
 	}
 
+	if status == HTTPStatusOk && (r.Method == http.MethodGet || r.Method == http.MethodHead) &&
+		w.Header().Get("Cache-Control") != cacheControlNoStore {
+		etag := ETag(responseSend)
+		w.Header().Set("ETag", etag)
+
+		if MatchETag(r.Header.Get("If-None-Match"), etag) {
+			w.Header().Del("Content-Type")
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+	}
+
+	w.WriteHeader(status)
+
 	_, err = w.Write(responseSend)
 	if err != nil {
 		logger.Errorf("in sendResponse: %+v\n", err)
-		http.Error(w, ErrInternalServer, http.StatusInternalServerError)
 	}
 }
 
-func SendErrResponse(w http.ResponseWriter, logger *zap.SugaredLogger, response any) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(HTTPStatusError)
-	sendResponse(w, logger, response)
+// SendErrResponse writes error localized to language of request in format chosen for request:
+// legacy envelope with HTTPStatusError or problem+json with real http status. Legacy envelope of
+// authentication, rate limit and overload errors keeps real http status too, clients and proxies must not take
+// it for success.
+func SendErrResponse(w http.ResponseWriter, r *http.Request, logger *zap.SugaredLogger, err *myerrors.Error,
+	fieldErrors ...myerrors.FieldError,
+) {
+	lang := LangFromContext(r.Context())
+	message := err.Localize(lang)
+
+	localizedFieldErrors := make([]myerrors.FieldError, 0, len(fieldErrors))
+	for _, fieldError := range fieldErrors {
+		localizedFieldErrors = append(localizedFieldErrors, fieldError.Localize(lang))
+	}
+
+	if ErrorFormatFromContext(r.Context()) == ErrorFormatLegacy {
+		status := HTTPStatusError
+		switch err.Status() {
+		case http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests,
+			http.StatusServiceUnavailable:
+			status = err.Status()
+		}
+
+		w.Header().Set("Content-Type", ContentTypeJSON)
+		sendResponse(w, r, logger, status, NewErrResponse(err.Status(), err.Code(), message, localizedFieldErrors...))
+
+		return
+	}
+
+	w.Header().Set("Content-Type", ContentTypeProblem)
+	sendResponse(w, r, logger, err.Status(),
+		NewProblem(err.Status(), err.Code(), message, r.URL.Path, localizedFieldErrors...))
+}
+
+func SendOkResponse(w http.ResponseWriter, r *http.Request, logger *zap.SugaredLogger, response any) {
+	w.Header().Set("Content-Type", ContentTypeJSON)
+	sendResponse(w, r, logger, HTTPStatusOk, response)
+}
+
+// SendCreatedResponse writes json of created resource with http.StatusCreated and Location of it.
+func SendCreatedResponse(w http.ResponseWriter, r *http.Request, logger *zap.SugaredLogger, location string,
+	response any,
+) {
+	w.Header().Set("Location", location)
+	w.Header().Set("Content-Type", ContentTypeJSON)
+	sendResponse(w, r, logger, http.StatusCreated, response)
+}
+
+// SendNoContent writes http.StatusNoContent without body.
+func SendNoContent(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNoContent)
 }
 
-func SendOkResponse(w http.ResponseWriter, logger *zap.SugaredLogger, response any) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(HTTPStatusOk)
-	sendResponse(w, logger, response)
+// SendStatusResponse writes json response with http status other than HTTPStatusOk, e.g. for probes of orchestrator.
+func SendStatusResponse(w http.ResponseWriter, r *http.Request, logger *zap.SugaredLogger, status int,
+	response any,
+) {
+	w.Header().Set("Content-Type", ContentTypeJSON)
+	sendResponse(w, r, logger, status, response)
 }
@@ -0,0 +1,31 @@
+package delivery
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+)
+
+// ClientIP returns ip address of peer which sent request.
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// ClientKey identifies client of request by API key, user or ip address, in that order.
+// Request must be authenticated before.
+func ClientKey(r *http.Request) string {
+	if apiKey := APIKeyFromContext(r.Context()); apiKey != nil {
+		return "key:" + strconv.FormatUint(apiKey.ID, 10)
+	}
+
+	if user := UserFromContext(r.Context()); user != nil {
+		return "user:" + strconv.FormatUint(user.ID, 10)
+	}
+
+	return "ip:" + ClientIP(r)
+}
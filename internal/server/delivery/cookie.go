@@ -0,0 +1,38 @@
+package delivery
+
+import (
+	"net/http"
+	"time"
+)
+
+const (
+	cookieAuthPath = "/api"
+)
+
+// NewAuthCookie returns cookie with session token, it isn't available to scripts and, if secure is set,
+// sent only over https.
+func NewAuthCookie(token string, expiresAt time.Time, secure bool) *http.Cookie {
+	return &http.Cookie{ //nolint:exhaustruct
+		Name:     CookieAuthName,
+		Value:    token,
+		Path:     cookieAuthPath,
+		Expires:  expiresAt,
+		MaxAge:   int(time.Until(expiresAt).Seconds()),
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// ExpiredAuthCookie returns cookie which removes session token from client.
+func ExpiredAuthCookie(secure bool) *http.Cookie {
+	return &http.Cookie{ //nolint:exhaustruct
+		Name:     CookieAuthName,
+		Value:    "",
+		Path:     cookieAuthPath,
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
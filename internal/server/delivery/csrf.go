@@ -0,0 +1,22 @@
+package delivery
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+const (
+	HeaderCSRFToken = "X-CSRF-Token"
+
+	csrfTokenPurpose = "csrf"
+)
+
+// CSRFToken returns anti-CSRF token of session. It is derived from session token, which scripts of other
+// sites can't read, so it can't be forged and needs no storage.
+func CSRFToken(sessionToken string) string {
+	mac := hmac.New(sha256.New, []byte(sessionToken))
+	mac.Write([]byte(csrfTokenPurpose))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -7,13 +7,24 @@ import (
 	"net/http"
 )
 
-func HandleErr(w http.ResponseWriter, logger *zap.SugaredLogger, err error) {
+type fieldErrorsProvider interface {
+	FieldErrors() []myerrors.FieldError
+}
+
+func HandleErr(w http.ResponseWriter, r *http.Request, logger *zap.SugaredLogger, err error) {
+	var fieldErrors []myerrors.FieldError
+
+	var provider fieldErrorsProvider
+	if errors.As(err, &provider) {
+		fieldErrors = provider.FieldErrors()
+	}
+
 	myErr := &myerrors.Error{}
 	if errors.As(err, &myErr) {
-		SendErrResponse(w, logger, NewErrResponse(StatusErrBadRequest, err.Error()))
+		SendErrResponse(w, r, logger, myErr, fieldErrors...)
 
 		return
 	}
 
-	SendErrResponse(w, logger, NewErrResponse(StatusErrInternalServer, ErrInternalServer))
+	SendErrResponse(w, r, logger, ErrInternalServer)
 }
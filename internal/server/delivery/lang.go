@@ -0,0 +1,22 @@
+package delivery
+
+import (
+	"context"
+	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
+)
+
+type langKey struct{}
+
+func WithLang(ctx context.Context, lang myerrors.Lang) context.Context {
+	return context.WithValue(ctx, langKey{}, lang)
+}
+
+// LangFromContext returns myerrors.DefaultLang if language wasn't set.
+func LangFromContext(ctx context.Context) myerrors.Lang {
+	lang, ok := ctx.Value(langKey{}).(myerrors.Lang)
+	if !ok {
+		return myerrors.DefaultLang
+	}
+
+	return lang
+}
@@ -2,34 +2,58 @@ package mux
 
 import (
 	"context"
+	"github.com/SanExpett/auto-catalog/internal/server/delivery"
+	"github.com/SanExpett/auto-catalog/pkg/metrics"
 	"github.com/SanExpett/auto-catalog/pkg/middleware"
+	"github.com/SanExpett/auto-catalog/pkg/models"
+	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
+	"github.com/SanExpett/auto-catalog/pkg/ratelimit"
 	"net/http"
 
+	admindelivery "github.com/SanExpett/auto-catalog/internal/admin/delivery"
 	cardelivery "github.com/SanExpett/auto-catalog/internal/car/delivery"
+	docsdelivery "github.com/SanExpett/auto-catalog/internal/docs/delivery"
+	healthdelivery "github.com/SanExpett/auto-catalog/internal/health/delivery"
 	peopledelivery "github.com/SanExpett/auto-catalog/internal/people/delivery"
+	userdelivery "github.com/SanExpett/auto-catalog/internal/user/delivery"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
+// cacheControlDocs lets documentation be cached for a while, it changes only with deploys.
+const cacheControlDocs = "public, max-age=300"
+
 type ConfigMux struct {
-	addrOrigin string
-	schema     string
-	portServer string
+	corsPolicy           *middleware.CORSPolicy
+	compressionMinSize   int
+	portServer           string
+	legacyErrorResponses bool
+	defaultLang          myerrors.Lang
+	docsEnabled          bool
+	secureCookie         bool
 }
 
-func NewConfigMux(addrOrigin string, schema string, portServer string) *ConfigMux {
+// NewConfigMux describes mux, compressionMinSize 0 disables compression of responses.
+func NewConfigMux(corsPolicy *middleware.CORSPolicy, compressionMinSize int, portServer string,
+	legacyErrorResponses bool, defaultLang myerrors.Lang, docsEnabled bool, secureCookie bool,
+) *ConfigMux {
 	return &ConfigMux{
-		addrOrigin: addrOrigin,
-		schema:     schema,
-		portServer: portServer,
+		corsPolicy:           corsPolicy,
+		compressionMinSize:   compressionMinSize,
+		portServer:           portServer,
+		legacyErrorResponses: legacyErrorResponses,
+		defaultLang:          defaultLang,
+		docsEnabled:          docsEnabled,
+		secureCookie:         secureCookie,
 	}
 }
 
 func NewMux(ctx context.Context, configMux *ConfigMux, peopleService peopledelivery.IPeopleService,
-	carService cardelivery.ICarService, logger *zap.SugaredLogger,
+	carService cardelivery.ICarService, healthService healthdelivery.IHealthService,
+	userService userdelivery.IUserService, apiKeyAuthenticator middleware.APIKeyAuthenticator,
+	limiter *ratelimit.Limiter, concurrencyLimiter *middleware.ConcurrencyLimiter, logger *zap.SugaredLogger,
 ) (http.Handler, error) {
-	router := http.NewServeMux()
-
 	peopleHandler, err := peopledelivery.NewPeopleHandler(peopleService)
 	if err != nil {
 		return nil, err
@@ -40,26 +64,179 @@ func NewMux(ctx context.Context, configMux *ConfigMux, peopleService peopledeliv
 		return nil, err
 	}
 
-	router.Handle("/api/v1/people/add", middleware.Context(ctx,
-		middleware.SetupCORS(peopleHandler.AddPeopleHandler, configMux.addrOrigin, configMux.schema)))
-	router.Handle("/api/v1/people/get", middleware.Context(ctx,
-		middleware.SetupCORS(peopleHandler.GetPeopleHandler, configMux.addrOrigin, configMux.schema)))
-	router.Handle("/api/v1/people/delete", middleware.Context(ctx,
-		middleware.SetupCORS(peopleHandler.DeletePeopleHandler, configMux.addrOrigin, configMux.schema)))
-
-	router.Handle("/api/v1/car/add", middleware.Context(ctx,
-		middleware.SetupCORS(carHandler.AddCarHandler, configMux.addrOrigin, configMux.schema)))
-	router.Handle("/api/v1/car/get", middleware.Context(ctx,
-		middleware.SetupCORS(carHandler.GetCarHandler, configMux.addrOrigin, configMux.schema)))
-	router.Handle("/api/v1/car/delete", middleware.Context(ctx,
-		middleware.SetupCORS(carHandler.DeleteCarHandler, configMux.addrOrigin, configMux.schema)))
-	router.Handle("/api/v1/car/update", middleware.Context(ctx,
-		middleware.SetupCORS(carHandler.UpdateCarHandler, configMux.addrOrigin, configMux.schema)))
-	router.Handle("/api/v1/car/get_list", middleware.Context(ctx,
-		middleware.SetupCORS(carHandler.GetCarsListHandler, configMux.addrOrigin, configMux.schema)))
+	healthHandler, err := healthdelivery.NewHealthHandler(healthService)
+	if err != nil {
+		return nil, err
+	}
+
+	userHandler, err := userdelivery.NewUserHandler(userService, configMux.secureCookie)
+	if err != nil {
+		return nil, err
+	}
+
+	// v1 keeps old error envelope for compatibility if it is enabled in config, v2 always uses problem+json
+	formatV1 := delivery.ErrorFormatProblem
+	if configMux.legacyErrorResponses {
+		formatV1 = delivery.ErrorFormatLegacy
+	}
+
+	// requests over concurrency limit are shed before any work, but still logged and counted. Limit is inside
+	// CORS, so browsers see 503 of shed requests and preflight requests are never shed. Nil limiter doesn't limit
+	chain := func(format delivery.ErrorFormat, limiter *middleware.ConcurrencyLimiter, router http.Handler,
+	) http.Handler {
+		handler := middleware.ErrorFormat(format, middleware.Lang(configMux.defaultLang, middleware.Panic(
+			middleware.SetupCORS(configMux.corsPolicy, middleware.LimitConcurrency(limiter, router)), logger)))
+
+		if configMux.compressionMinSize != 0 {
+			handler = middleware.Compress(configMux.compressionMinSize, handler)
+		}
+
+		return middleware.Context(ctx, middleware.AccessLog(logger, zapcore.InfoLevel, middleware.Metrics(handler)))
+	}
+
+	// requests are authenticated by API key or session cookie, scopes of routes are checked by routers
+	authenticated := func(router http.Handler) http.Handler {
+		return middleware.Auth(apiKeyAuthenticator, userService, configMux.secureCookie, middleware.CSRF(router))
+	}
+
+	// clients are identified for rate limits after authentication
+	routerV1 := authenticated(NewRouter(routesV1(peopleHandler, carHandler)).WithRateLimiter(limiter))
+	routerV2 := authenticated(NewRouter(routesV2(peopleHandler, carHandler, userHandler)).
+		WithRateLimiter(limiter))
 
 	mux := http.NewServeMux()
-	mux.Handle("/", middleware.Panic(router, logger))
+
+	// probes of orchestrator and scrapes go around CORS, API keys and concurrency limit, they only have to
+	// survive panic and are logged at debug level not to flood logs
+	routerHealth := middleware.AccessLog(logger, zapcore.DebugLevel, middleware.Metrics(
+		middleware.Panic(NewRouter(routesHealth(healthHandler)), logger)))
+	mux.Handle("/healthz", routerHealth)
+	mux.Handle("/readyz", routerHealth)
+	mux.Handle("/metrics", middleware.AccessLog(logger, zapcore.DebugLevel,
+		middleware.Panic(NewRouter(routesMetrics()), logger)))
+
+	mux.Handle("/api/v1/", middleware.Deprecation("/api/v2", chain(formatV1, concurrencyLimiter, routerV1)))
+	mux.Handle("/api/v2/", chain(delivery.ErrorFormatProblem, concurrencyLimiter, routerV2))
+
+	if configMux.docsEnabled {
+		docsHandler, err := docsdelivery.NewDocsHandler()
+		if err != nil {
+			return nil, err
+		}
+
+		mux.Handle("/api/docs", chain(delivery.ErrorFormatProblem, concurrencyLimiter,
+			NewRouter(routesDocs(docsHandler))))
+		mux.Handle("/api/docs/", chain(delivery.ErrorFormatProblem, concurrencyLimiter,
+			NewRouter(routesDocs(docsHandler))))
+	}
+
+	adminHandler, err := admindelivery.NewAdminHandler(limiter)
+	if err != nil {
+		return nil, err
+	}
+
+	// admin endpoints are neither rate limited nor shed, operators must be able to relax too strict limits
+	// of overloaded server
+	mux.Handle("/api/admin/", chain(delivery.ErrorFormatProblem, nil,
+		authenticated(NewRouter(routesAdmin(adminHandler)))))
 
 	return mux, nil
 }
+
+func routesV1(peopleHandler *peopledelivery.PeopleHandler, carHandler *cardelivery.CarHandler) []Route {
+	return []Route{
+		{Method: http.MethodPost, Pattern: "/api/v1/people/add", Scope: models.ScopePeopleWrite,
+			Handler: peopleHandler.AddPeopleHandler},
+		{Method: http.MethodGet, Pattern: "/api/v1/people/get", Scope: models.ScopePeopleRead,
+			Handler: peopleHandler.GetPeopleHandler},
+		{Method: http.MethodDelete, Pattern: "/api/v1/people/delete", Scope: models.ScopePeopleWrite,
+			Handler: peopleHandler.DeletePeopleHandler},
+
+		{Method: http.MethodPost, Pattern: "/api/v1/car/add", Scope: models.ScopeCarsWrite,
+			Handler: carHandler.AddCarHandler},
+		{Method: http.MethodGet, Pattern: "/api/v1/car/get", Scope: models.ScopeCarsRead,
+			Handler: carHandler.GetCarHandler},
+		{Method: http.MethodDelete, Pattern: "/api/v1/car/delete", Scope: models.ScopeCarsWrite,
+			Handler: carHandler.DeleteCarHandler},
+		{Method: http.MethodPatch, Pattern: "/api/v1/car/update", Scope: models.ScopeCarsWrite,
+			Handler: carHandler.UpdateCarHandler},
+		{Method: http.MethodPut, Pattern: "/api/v1/car/update", Scope: models.ScopeCarsWrite,
+			Handler: carHandler.UpdateCarHandler},
+		{Method: http.MethodGet, Pattern: "/api/v1/car/get_list", Scope: models.ScopeCarsRead,
+			Handler: carHandler.GetCarsListHandler},
+	}
+}
+
+func routesHealth(healthHandler *healthdelivery.HealthHandler) []Route {
+	return []Route{
+		{Method: http.MethodGet, Pattern: "/healthz", CacheControl: CacheControlNoStore,
+			Handler: healthHandler.HealthzHandler},
+		{Method: http.MethodGet, Pattern: "/readyz", CacheControl: CacheControlNoStore,
+			Handler: healthHandler.ReadyzHandler},
+	}
+}
+
+func routesMetrics() []Route {
+	return []Route{
+		{Method: http.MethodGet, Pattern: "/metrics", CacheControl: CacheControlNoStore,
+			Handler: metrics.Handler().ServeHTTP},
+	}
+}
+
+func routesAdmin(adminHandler *admindelivery.AdminHandler) []Route {
+	return []Route{
+		{Method: http.MethodGet, Pattern: "/api/admin/log/level", Scope: models.ScopeAdmin,
+			CacheControl: CacheControlNoStore, Handler: adminHandler.GetLogLevelHandler},
+		{Method: http.MethodPut, Pattern: "/api/admin/log/level", Scope: models.ScopeAdmin,
+			Handler: adminHandler.SetLogLevelHandler},
+		{Method: http.MethodGet, Pattern: "/api/admin/ratelimit", Scope: models.ScopeAdmin,
+			CacheControl: CacheControlNoStore, Handler: adminHandler.GetRateLimitsHandler},
+		{Method: http.MethodPut, Pattern: "/api/admin/ratelimit", Scope: models.ScopeAdmin,
+			Handler: adminHandler.SetRateLimitsHandler},
+	}
+}
+
+func routesDocs(docsHandler *docsdelivery.DocsHandler) []Route {
+	return []Route{
+		{Method: http.MethodGet, Pattern: "/api/docs", CacheControl: cacheControlDocs,
+			Handler: docsHandler.GetExplorerHandler},
+		{Method: http.MethodGet, Pattern: docsdelivery.SpecURL, CacheControl: cacheControlDocs,
+			Handler: docsHandler.GetSpecHandler},
+	}
+}
+
+func routesV2(peopleHandler *peopledelivery.PeopleHandler, carHandler *cardelivery.CarHandler,
+	userHandler *userdelivery.UserHandler,
+) []Route {
+	return []Route{
+		{Method: http.MethodPost, Pattern: "/api/v2/auth/register", Handler: userHandler.RegisterHandler},
+		{Method: http.MethodPost, Pattern: "/api/v2/auth/login", Handler: userHandler.LoginHandler},
+		{Method: http.MethodPost, Pattern: "/api/v2/auth/logout", Handler: userHandler.LogoutHandler},
+		{Method: http.MethodGet, Pattern: "/api/v2/auth/me", CacheControl: CacheControlNoStore,
+			Handler: userHandler.MeHandler},
+		{Method: http.MethodGet, Pattern: "/api/v2/auth/csrf", CacheControl: CacheControlNoStore,
+			Handler: userHandler.CSRFTokenHandler},
+
+		{Method: http.MethodPost, Pattern: "/api/v2/people", Scope: models.ScopePeopleWrite,
+			Handler: peopleHandler.AddPeopleV2Handler},
+		{Method: http.MethodGet, Pattern: "/api/v2/people/{id}", Scope: models.ScopePeopleRead,
+			Handler: peopleHandler.GetPeopleV2Handler},
+		{Method: http.MethodDelete, Pattern: "/api/v2/people/{id}", Scope: models.ScopePeopleWrite,
+			Handler: peopleHandler.DeletePeopleV2Handler},
+		{Method: http.MethodGet, Pattern: "/api/v2/people/{owner_id}/cars", Scope: models.ScopeCarsRead,
+			Handler: carHandler.GetCarsOfOwnerV2Handler},
+
+		{Method: http.MethodGet, Pattern: "/api/v2/cars", Scope: models.ScopeCarsRead,
+			Handler: carHandler.GetCarsListV2Handler},
+		{Method: http.MethodPost, Pattern: "/api/v2/cars", Scope: models.ScopeCarsWrite,
+			Handler: carHandler.AddCarV2Handler},
+		{Method: http.MethodGet, Pattern: "/api/v2/cars/{id}", Scope: models.ScopeCarsRead,
+			Handler: carHandler.GetCarV2Handler},
+		{Method: http.MethodPatch, Pattern: "/api/v2/cars/{id}", Scope: models.ScopeCarsWrite,
+			Handler: carHandler.UpdateCarV2Handler},
+		{Method: http.MethodPut, Pattern: "/api/v2/cars/{id}", Scope: models.ScopeCarsWrite,
+			Handler: carHandler.UpdateCarV2Handler},
+		{Method: http.MethodDelete, Pattern: "/api/v2/cars/{id}", Scope: models.ScopeCarsWrite,
+			Handler: carHandler.DeleteCarV2Handler},
+	}
+}
@@ -0,0 +1,234 @@
+package mux
+
+import (
+	"github.com/SanExpett/auto-catalog/internal/server/delivery"
+	"github.com/SanExpett/auto-catalog/pkg/metrics"
+	"github.com/SanExpett/auto-catalog/pkg/models"
+	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
+	"github.com/SanExpett/auto-catalog/pkg/my_logger"
+	"github.com/SanExpett/auto-catalog/pkg/ratelimit"
+	"github.com/SanExpett/auto-catalog/pkg/utils"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	MessageErrForbidden   = "forbidden"
+	MessageErrRateLimited = "rate_limited"
+
+	CacheControlRevalidate = "private, no-cache"
+	CacheControlNoStore    = "no-store"
+)
+
+var (
+	ErrRouteNotFound    = myerrors.NewNotFoundError("route_not_found")
+	ErrMethodNotAllowed = myerrors.NewMethodNotAllowedError("method_not_allowed")
+)
+
+// Route binds handler to method and pattern. Pattern segments like {id} are path parameters,
+// they are available for handlers through utils.ParseUint64FromRequest. If Scope is set, route is
+// served only for requests of API key or user with this scope. RateClass is class of rate limit of route,
+// by default GET and HEAD requests are reads and others are writes. CacheControl is policy of caching of
+// responses, by default reads are revalidated by etag every time and writes are not stored.
+type Route struct {
+	Method       string
+	Pattern      string
+	Scope        models.Scope
+	RateClass    ratelimit.Class
+	CacheControl string
+	Handler      http.HandlerFunc
+}
+
+type Router struct {
+	routes  []Route
+	limiter *ratelimit.Limiter
+}
+
+func NewRouter(routes []Route) *Router {
+	return &Router{routes: routes, limiter: nil}
+}
+
+// WithRateLimiter makes router limit requests of every client by limiter, routers without it don't limit requests.
+func (rt *Router) WithRateLimiter(limiter *ratelimit.Limiter) *Router {
+	rt.limiter = limiter
+
+	return rt
+}
+
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var allowedMethods []string
+
+	for _, route := range rt.routes {
+		params, ok := matchPattern(route.Pattern, r.URL.Path)
+		if !ok {
+			continue
+		}
+
+		if !methodMatches(route.Method, r.Method) {
+			allowedMethods = append(allowedMethods, route.Method)
+			if route.Method == http.MethodGet {
+				allowedMethods = append(allowedMethods, http.MethodHead)
+			}
+
+			continue
+		}
+
+		delivery.SetRoute(r.Context(), route.Pattern)
+		w.Header().Set("Cache-Control", cacheControl(route))
+
+		// requests without scope are rejected before rate limit, so they don't take tokens of clients
+		// and don't make buckets for every anonymous address
+		if route.Scope != "" && !rt.checkScope(w, r, route.Scope) {
+			return
+		}
+
+		if rt.limiter != nil && !rt.checkRateLimit(w, r, rateClass(route)) {
+			return
+		}
+
+		r = r.WithContext(utils.WithPathParams(r.Context(), params))
+		route.Handler.ServeHTTP(w, r)
+
+		return
+	}
+
+	if len(allowedMethods) != 0 {
+		w.Header().Set("Allow", strings.Join(append(allowedMethods, http.MethodOptions), ", "))
+		rt.sendErr(w, r, ErrMethodNotAllowed)
+
+		return
+	}
+
+	rt.sendErr(w, r, ErrRouteNotFound)
+}
+
+// checkScope sends 401 or 403 error and returns false if neither API key nor user of request has scope.
+func (rt *Router) checkScope(w http.ResponseWriter, r *http.Request, scope models.Scope) bool {
+	apiKey := delivery.APIKeyFromContext(r.Context())
+	user := delivery.UserFromContext(r.Context())
+
+	if apiKey == nil && user == nil {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		delivery.SendErrResponse(w, r, my_logger.FromContext(r.Context()), delivery.ErrUnauthorized)
+
+		return false
+	}
+
+	if !apiKey.HasScope(scope) && !user.HasScope(scope) {
+		delivery.SendErrResponse(w, r, my_logger.FromContext(r.Context()),
+			myerrors.NewForbiddenError(MessageErrForbidden, scope))
+
+		return false
+	}
+
+	return true
+}
+
+// checkRateLimit takes request from bucket of client in class, sets RateLimit headers and sends 429 error
+// and returns false if client has exhausted limit.
+func (rt *Router) checkRateLimit(w http.ResponseWriter, r *http.Request, class ratelimit.Class) bool {
+	result := rt.limiter.Allow(class, delivery.ClientKey(r))
+	if result.Limit.IsUnlimited() {
+		return true
+	}
+
+	header := w.Header()
+	header.Set("RateLimit-Policy", strconv.Itoa(result.Limit.Requests)+";w="+ceilSeconds(result.Limit.Window))
+	header.Set("RateLimit-Limit", strconv.Itoa(result.Limit.Requests))
+	header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	header.Set("RateLimit-Reset", ceilSeconds(result.Reset))
+
+	if result.Allowed {
+		return true
+	}
+
+	retryAfter := ceilSeconds(max(result.RetryAfter, time.Second))
+	header.Set("Retry-After", retryAfter)
+	metrics.IncRateLimited(string(class))
+	delivery.SendErrResponse(w, r, my_logger.FromContext(r.Context()),
+		myerrors.NewTooManyRequestsError(MessageErrRateLimited, retryAfter))
+
+	return false
+}
+
+func rateClass(route Route) ratelimit.Class {
+	if route.RateClass != "" {
+		return route.RateClass
+	}
+
+	if route.Method == http.MethodGet || route.Method == http.MethodHead {
+		return ratelimit.ClassRead
+	}
+
+	return ratelimit.ClassWrite
+}
+
+func cacheControl(route Route) string {
+	if route.CacheControl != "" {
+		return route.CacheControl
+	}
+
+	if route.Method == http.MethodGet || route.Method == http.MethodHead {
+		return CacheControlRevalidate
+	}
+
+	return CacheControlNoStore
+}
+
+// ceilSeconds formats duration as whole seconds rounded up, as RateLimit and Retry-After headers require.
+func ceilSeconds(duration time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(duration.Seconds())), 10)
+}
+
+// sendErr keeps plain text errors of net/http for clients of legacy format.
+func (rt *Router) sendErr(w http.ResponseWriter, r *http.Request, err *myerrors.Error) {
+	if delivery.ErrorFormatFromContext(r.Context()) == delivery.ErrorFormatLegacy {
+		http.Error(w, http.StatusText(err.Status()), err.Status())
+
+		return
+	}
+
+	delivery.SendErrResponse(w, r, my_logger.FromContext(r.Context()), err)
+}
+
+// methodMatches reports whether request of method is served by route of routeMethod. HEAD is served by GET
+// routes, net/http drops body of response to HEAD request.
+func methodMatches(routeMethod string, method string) bool {
+	return routeMethod == method || routeMethod == http.MethodGet && method == http.MethodHead
+}
+
+func matchPattern(pattern string, path string) (map[string]string, bool) {
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+
+	patternSegments := strings.Split(pattern, "/")
+	pathSegments := strings.Split(path, "/")
+
+	if len(patternSegments) != len(pathSegments) {
+		return nil, false
+	}
+
+	params := make(map[string]string)
+
+	for i, patternSegment := range patternSegments {
+		if strings.HasPrefix(patternSegment, "{") && strings.HasSuffix(patternSegment, "}") {
+			if pathSegments[i] == "" {
+				return nil, false
+			}
+
+			params[patternSegment[1:len(patternSegment)-1]] = pathSegments[i]
+
+			continue
+		}
+
+		if patternSegment != pathSegments[i] {
+			return nil, false
+		}
+	}
+
+	return params, true
+}
@@ -0,0 +1,61 @@
+package delivery
+
+import (
+	"context"
+	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
+	"net/http"
+)
+
+const (
+	ProblemTypeDefault = "about:blank"
+)
+
+type ErrorFormat int
+
+const (
+	ErrorFormatProblem ErrorFormat = iota
+	ErrorFormatLegacy
+)
+
+type errorFormatKey struct{}
+
+// Problem is error body described in RFC 7807.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// Code is extension member with stable machine-readable code of error
+	Code string `json:"code"`
+	// Errors is extension member with all invalid fields of request
+	Errors []myerrors.FieldError `json:"errors,omitempty"`
+}
+
+func NewProblem(status int, code string, detail string, instance string,
+	fieldErrors ...myerrors.FieldError,
+) *Problem {
+	return &Problem{
+		Type:     ProblemTypeDefault,
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: instance,
+		Code:     code,
+		Errors:   fieldErrors,
+	}
+}
+
+func WithErrorFormat(ctx context.Context, format ErrorFormat) context.Context {
+	return context.WithValue(ctx, errorFormatKey{}, format)
+}
+
+// ErrorFormatFromContext returns ErrorFormatProblem if format wasn't set.
+func ErrorFormatFromContext(ctx context.Context) ErrorFormat {
+	format, ok := ctx.Value(errorFormatKey{}).(ErrorFormat)
+	if !ok {
+		return ErrorFormatProblem
+	}
+
+	return format
+}
@@ -0,0 +1,34 @@
+package delivery
+
+import "context"
+
+type routeKey struct{}
+
+// WithRouteHolder returns ctx in which router records pattern of matched route, so middlewares
+// wrapping router can label requests by route instead of raw path. Existing holder is kept.
+func WithRouteHolder(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(routeKey{}).(*string); ok {
+		return ctx
+	}
+
+	route := ""
+
+	return context.WithValue(ctx, routeKey{}, &route)
+}
+
+// SetRoute records pattern of matched route if ctx was prepared by WithRouteHolder.
+func SetRoute(ctx context.Context, pattern string) {
+	if route, ok := ctx.Value(routeKey{}).(*string); ok {
+		*route = pattern
+	}
+}
+
+// RouteFromContext returns pattern of matched route or empty string if no route matched.
+func RouteFromContext(ctx context.Context) string {
+	route, ok := ctx.Value(routeKey{}).(*string)
+	if !ok {
+		return ""
+	}
+
+	return *route
+}
@@ -0,0 +1,19 @@
+package delivery
+
+import (
+	"context"
+	"github.com/SanExpett/auto-catalog/pkg/models"
+)
+
+type userKey struct{}
+
+func WithUser(ctx context.Context, user *models.User) context.Context {
+	return context.WithValue(ctx, userKey{}, user)
+}
+
+// UserFromContext returns user logged in by session cookie or nil.
+func UserFromContext(ctx context.Context) *models.User {
+	user, _ := ctx.Value(userKey{}).(*models.User)
+
+	return user
+}
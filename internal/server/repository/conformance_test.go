@@ -0,0 +1,47 @@
+package repository_test
+
+import (
+	"context"
+	carrepo "github.com/SanExpett/auto-catalog/internal/car/repository"
+	"github.com/SanExpett/auto-catalog/internal/conformance"
+	peoplerepo "github.com/SanExpett/auto-catalog/internal/people/repository"
+	"github.com/SanExpett/auto-catalog/internal/server/repository"
+	"github.com/SanExpett/auto-catalog/pkg/config"
+	"testing"
+)
+
+// postgresStorage joins repositories of people and cars.
+type postgresStorage struct {
+	*peoplerepo.PeopleStorage
+	*carrepo.CarStorage
+}
+
+// TestConformance needs migrated database of URL_DATA_BASE and is skipped without it.
+func TestConformance(t *testing.T) {
+	configServer, err := config.LoadWithOverrides(map[string]string{"STORAGE": config.StoragePostgres})
+	if err != nil {
+		t.Skipf("no database: %v", err)
+	}
+
+	pool, err := repository.NewPgxPool(context.Background(), configServer.URLDataBase, configServer.DBMaxConns,
+		configServer.DBMinConns)
+	if err != nil {
+		t.Skipf("no database: %v", err)
+	}
+
+	t.Cleanup(pool.Close)
+
+	peopleStorage, err := peoplerepo.NewPeopleStorage(pool)
+	if err != nil {
+		t.Fatalf("people storage: %v", err)
+	}
+
+	carStorage, err := carrepo.NewCarStorage(pool)
+	if err != nil {
+		t.Fatalf("car storage: %v", err)
+	}
+
+	storage := postgresStorage{PeopleStorage: peopleStorage, CarStorage: carStorage}
+
+	conformance.RunConformance(t, func() conformance.Storage { return storage })
+}
@@ -9,11 +9,6 @@ import (
 )
 
 func GetLastValSeq(ctx context.Context, tx pgx.Tx, nameTable pgx.Identifier) (uint64, error) {
-	logger, err := my_logger.Get()
-	if err != nil {
-		return 0, fmt.Errorf(myerrors.ErrTemplate, err)
-	}
-
 	sanitizedNameTable := nameTable.Sanitize()
 	SQLGetLastValSeq := fmt.Sprintf(`SELECT last_value FROM %s;`, sanitizedNameTable)
 	seqRow := tx.QueryRow(ctx, SQLGetLastValSeq)
@@ -21,7 +16,7 @@ func GetLastValSeq(ctx context.Context, tx pgx.Tx, nameTable pgx.Identifier) (ui
 	var count uint64
 
 	if err := seqRow.Scan(&count); err != nil {
-		logger.Errorf("error in GetLastValSeq: %+v", err)
+		my_logger.FromContext(ctx).Errorf("error in GetLastValSeq: %+v", err)
 
 		return 0, fmt.Errorf(myerrors.ErrTemplate, err)
 	}
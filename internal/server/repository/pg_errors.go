@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"errors"
+	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	PgCodeForeignKeyViolation = "23503"
+	PgCodeUniqueViolation     = "23505"
+	PgCodeCheckViolation      = "23514"
+)
+
+// Constraint describes field protected by constraint and key of message shown to client when it is violated.
+type Constraint struct {
+	Field string
+	Key   string
+}
+
+// MapPgError translates unique, check and foreign key violations to *myerrors.ConstraintError
+// using constraints known by storage. Other errors are returned as is.
+func MapPgError(err error, constraints map[string]Constraint) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	var newErr func(key string, args ...any) *myerrors.Error
+
+	switch pgErr.Code {
+	case PgCodeUniqueViolation:
+		newErr = myerrors.NewConflictError
+	case PgCodeCheckViolation, PgCodeForeignKeyViolation:
+		newErr = myerrors.NewValidationError
+	default:
+		return err
+	}
+
+	var myErr *myerrors.Error
+
+	constraint, ok := constraints[pgErr.ConstraintName]
+	if ok {
+		myErr = newErr(constraint.Key)
+	} else {
+		constraint = Constraint{Field: pgErr.ColumnName, Key: "constraint_violation"}
+		myErr = newErr(constraint.Key, pgErr.ConstraintName)
+	}
+
+	return myerrors.NewConstraintError(myErr, constraint.Field, pgErr.ConstraintName)
+}
@@ -7,14 +7,24 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func NewPgxPool(ctx context.Context, urlDataBase string) (*pgxpool.Pool, error) {
-	pool, err := pgxpool.New(ctx, urlDataBase)
+func NewPgxPool(ctx context.Context, urlDataBase string, maxConns int32, minConns int32) (*pgxpool.Pool, error) {
+	poolConfig, err := pgxpool.ParseConfig(urlDataBase)
+	if err != nil {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	poolConfig.MaxConns = maxConns
+	poolConfig.MinConns = minConns
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
 	}
 
 	err = pool.Ping(ctx)
 	if err != nil {
+		pool.Close()
+
 		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
 	}
 
@@ -2,80 +2,493 @@ package server
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	apikeyrepo "github.com/SanExpett/auto-catalog/internal/apikey/repository"
+	apikeyusecases "github.com/SanExpett/auto-catalog/internal/apikey/usecases"
 	carrepo "github.com/SanExpett/auto-catalog/internal/car/repository"
 	carusecases "github.com/SanExpett/auto-catalog/internal/car/usecases"
+	healthrepo "github.com/SanExpett/auto-catalog/internal/health/repository"
+	healthusecases "github.com/SanExpett/auto-catalog/internal/health/usecases"
+	"github.com/SanExpett/auto-catalog/internal/memory"
+	"github.com/SanExpett/auto-catalog/internal/migrator"
 	peoplerepo "github.com/SanExpett/auto-catalog/internal/people/repository"
 	peopleusecases "github.com/SanExpett/auto-catalog/internal/people/usecases"
 	"github.com/SanExpett/auto-catalog/internal/server/delivery/mux"
 	"github.com/SanExpett/auto-catalog/internal/server/repository"
+	userrepo "github.com/SanExpett/auto-catalog/internal/user/repository"
+	userusecases "github.com/SanExpett/auto-catalog/internal/user/usecases"
+	"github.com/SanExpett/auto-catalog/pkg/cache"
 	"github.com/SanExpett/auto-catalog/pkg/config"
+	"github.com/SanExpett/auto-catalog/pkg/metrics"
+	"github.com/SanExpett/auto-catalog/pkg/middleware"
+	"github.com/SanExpett/auto-catalog/pkg/models"
 	"github.com/SanExpett/auto-catalog/pkg/my_logger"
+	"github.com/SanExpett/auto-catalog/pkg/ratelimit"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+	"net"
 	"net/http"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 )
 
 const (
-	basicTimeout = 10 * time.Second
+	carInfoAPIName = "car_info"
+
+	sessionsCleanupPeriod   = 10 * time.Minute
+	rateLimitsCleanupPeriod = time.Minute
 )
 
 type Server struct {
 	httpServer *http.Server
+	pool       *pgxpool.Pool
+	logger     *zap.SugaredLogger
+
+	// ready is false until server starts listening and after shutdown begins
+	ready atomic.Bool
+
+	workersCtx    context.Context //nolint:containedctx
+	cancelWorkers context.CancelFunc
+	workers       sync.WaitGroup
+
+	shutdownDelay time.Duration
 }
 
-func (s *Server) Run(config *config.Config) error {
-	baseCtx := context.Background()
+// Run serves requests until ctx is done, then shuts server down gracefully within config.ShutdownTimeout.
+func (s *Server) Run(ctx context.Context, config *config.Config) error {
+	// requests must not be canceled by signal, they are drained by Shutdown
+	baseCtx := context.WithoutCancel(ctx)
+
+	// memory storage needs no database
+	if !config.MemoryStorage() {
+		pool, err := repository.NewPgxPool(baseCtx, config.URLDataBase, config.DBMaxConns, config.DBMinConns)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
 
-	pool, err := repository.NewPgxPool(baseCtx, config.URLDataBase)
+		s.pool = pool
+	}
+
+	logger, err := NewLogger(config)
 	if err != nil {
+		if s.pool != nil {
+			s.pool.Close()
+		}
+
 		return err //nolint:wrapcheck
 	}
 
-	logger, err := my_logger.New(strings.Split(config.OutputLogPath, " "),
-		strings.Split(config.ErrorOutputLogPath, " "))
+	s.logger = logger
+
+	if s.pool != nil {
+		if err := metrics.Register(metrics.NewPoolCollector(s.pool)); err != nil {
+			return errors.Join(err, s.shutdownWithTimeout(config.ShutdownTimeout))
+		}
+
+		if err := migrate(baseCtx, s.pool, config.AutoMigrate); err != nil {
+			return errors.Join(err, s.shutdownWithTimeout(config.ShutdownTimeout))
+		}
+	} else {
+		logger.Warnf("Data is kept in memory and will be lost on shutdown")
+	}
+
+	s.workersCtx, s.cancelWorkers = context.WithCancel(baseCtx)
+	s.shutdownDelay = config.ShutdownDelay
+
+	handler, err := s.newHandler(baseCtx, config)
 	if err != nil {
-		return err //nolint:wrapcheck
+		return errors.Join(err, s.shutdownWithTimeout(config.ShutdownTimeout))
 	}
 
-	defer logger.Sync()
+	s.httpServer = &http.Server{ //nolint:exhaustruct
+		Addr:           ":" + config.PortServer,
+		Handler:        handler,
+		MaxHeaderBytes: http.DefaultMaxHeaderBytes,
+		ReadTimeout:    config.ReadTimeout,
+		WriteTimeout:   config.WriteTimeout,
+		IdleTimeout:    config.IdleTimeout,
+	}
 
-	peopleStorage, err := peoplerepo.NewPeopleStorage(pool)
+	listener, err := net.Listen("tcp", s.httpServer.Addr)
 	if err != nil {
+		return errors.Join(err, s.shutdownWithTimeout(config.ShutdownTimeout))
+	}
+
+	serveErr := make(chan error, 1)
+
+	go func() {
+		serveErr <- s.httpServer.Serve(listener)
+	}()
+
+	s.ready.Store(true)
+	logger.Infof("Start server:%s", config.PortServer)
+
+	select {
+	case err := <-serveErr:
+		return errors.Join(err, s.shutdownWithTimeout(config.ShutdownTimeout))
+	case <-ctx.Done():
+		logger.Infof("Shutdown server: %v", context.Cause(ctx))
+	}
+
+	if err := s.shutdownWithTimeout(config.ShutdownTimeout); err != nil {
 		return err
 	}
-	peopleService, err := peopleusecases.NewPeopleService(peopleStorage)
+
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err //nolint:wrapcheck
+	}
+
+	return nil
+}
+
+func (s *Server) newHandler(baseCtx context.Context, config *config.Config) (http.Handler, error) {
+	storages, err := s.newStorages(config)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	carStorage, err := carrepo.NewCarStorage(pool)
+	peopleService, err := peopleusecases.NewPeopleService(storages.people)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	carService, err := carusecases.NewCarService(carStorage)
+	carService, err := carusecases.NewCarService(storages.car)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	handler, err := mux.NewMux(baseCtx, mux.NewConfigMux(config.AllowOrigin,
-		config.Schema, config.PortServer), peopleService, carService, logger)
+	apiKeyService, err := apikeyusecases.NewAPIKeyService(storages.apiKey)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	s.httpServer = &http.Server{ //nolint:exhaustruct
-		Addr:           ":" + config.PortServer,
-		Handler:        handler,
-		MaxHeaderBytes: http.DefaultMaxHeaderBytes,
-		ReadTimeout:    basicTimeout,
-		WriteTimeout:   basicTimeout,
+	userService, err := userusecases.NewUserService(storages.user, storages.session, config.SessionIdleTimeout,
+		config.SessionMaxAge)
+	if err != nil {
+		return nil, err
 	}
 
-	logger.Infof("Start server:%s", config.PortServer)
+	s.goWorker(func(ctx context.Context) {
+		cleanupSessions(ctx, userService)
+	})
+
+	limiter := ratelimit.NewLimiter(map[ratelimit.Class]ratelimit.Limit{
+		ratelimit.ClassRead:  config.RateLimitRead,
+		ratelimit.ClassWrite: config.RateLimitWrite,
+	})
+
+	s.goWorker(func(ctx context.Context) {
+		cleanupRateLimits(ctx, limiter)
+	})
+
+	healthService, err := s.newHealthService(config)
+	if err != nil {
+		return nil, err
+	}
+
+	corsPolicy, err := middleware.NewCORSPolicy(config.AllowOrigins, config.Schema, config.CORSAllowMethods,
+		config.CORSAllowHeaders, config.CORSMaxAge)
+	if err != nil {
+		return nil, fmt.Errorf("ALLOW_ORIGIN: %w", err)
+	}
+
+	return mux.NewMux(baseCtx, mux.NewConfigMux(corsPolicy, config.CompressionMinSize, config.PortServer,
+		config.LegacyErrorResponses, config.DefaultLang, config.DocsEnabled, config.SessionCookieSecure),
+		peopleService, carService, healthService, userService, apiKeyService, limiter,
+		middleware.NewConcurrencyLimiter(config.MaxInFlight, config.MaxQueued, config.QueueTimeout), s.logger)
+}
+
+// storages are storages of server, they keep data in postgres or in memory as config.Storage says.
+type storages struct {
+	people  peopleusecases.IPeopleStorage
+	car     carusecases.ICarStorage
+	user    userusecases.IUserStorage
+	session userusecases.ISessionStorage
+	apiKey  apikeyusecases.IAPIKeyStorage
+}
+
+func (s *Server) newStorages(config *config.Config) (*storages, error) {
+	var (
+		result *storages
+		err    error
+	)
+
+	if s.pool == nil {
+		result, err = newMemoryStorages()
+	} else {
+		result, err = s.newPostgresStorages()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.people, result.car, err = newCatalogStorages(config, result.people, result.car)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func newMemoryStorages() (*storages, error) {
+	storage, err := memory.NewStorage()
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return &storages{people: storage, car: storage, user: storage, session: storage, apiKey: storage}, nil
+}
+
+func (s *Server) newPostgresStorages() (*storages, error) {
+	peopleStorage, err := peoplerepo.NewPeopleStorage(s.pool)
+	if err != nil {
+		return nil, err
+	}
+
+	carStorage, err := carrepo.NewCarStorage(s.pool)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKeyStorage, err := apikeyrepo.NewAPIKeyStorage(s.pool)
+	if err != nil {
+		return nil, err
+	}
+
+	userStorage, err := userrepo.NewUserStorage(s.pool)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionStorage, err := userrepo.NewSessionStorage(s.pool)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storages{people: peopleStorage, car: carStorage, user: userStorage, session: sessionStorage,
+		apiKey: apiKeyStorage}, nil
+}
+
+// newCatalogStorages wraps storages of people and cars, reads by id go through cache if it is enabled.
+func newCatalogStorages(config *config.Config, peopleStorage peopleusecases.IPeopleStorage,
+	carStorage carusecases.ICarStorage,
+) (peopleusecases.IPeopleStorage, carusecases.ICarStorage, error) {
+	if !config.CacheEnabled {
+		return peopleStorage, carStorage, nil
+	}
+
+	peopleCache := cache.NewLRU[uint64, models.People]("people", config.CacheSize, config.CacheTTL)
+	carCache := cache.NewLRU[uint64, models.Car]("car", config.CacheSize, config.CacheTTL)
+
+	cachedPeopleStorage, err := peopleusecases.NewCachedPeopleStorage(peopleStorage, peopleCache, carCache)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cachedCarStorage, err := carusecases.NewCachedCarStorage(carStorage, carCache)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cachedPeopleStorage, cachedCarStorage, nil
+}
+
+func (s *Server) newHealthService(config *config.Config) (*healthusecases.HealthService, error) {
+	checks := []healthusecases.Check{
+		healthusecases.ServerCheck(s.Ready),
+	}
+
+	if s.pool != nil {
+		healthStorage, err := healthrepo.NewHealthStorage(s.pool)
+		if err != nil {
+			return nil, err
+		}
+
+		schemaMigrator, err := migrator.NewEmbeddedMigrator(s.pool)
+		if err != nil {
+			return nil, err
+		}
+
+		checks = append(checks, healthusecases.PostgresCheck(healthStorage),
+			healthusecases.MigrationsCheck(healthStorage, schemaMigrator.Latest()))
+	}
+
+	if config.CarInfoAPIURL != "" {
+		client := &http.Client{ //nolint:exhaustruct
+			Transport: metrics.InstrumentRoundTripper(carInfoAPIName, nil),
+			Timeout:   config.CarInfoAPITimeout,
+		}
+		checks = append(checks, healthusecases.CarInfoCheck(client, config.CarInfoAPIURL))
+	}
+
+	return healthusecases.NewHealthService(checks...)
+}
+
+// NewLogger creates global logger described by config.
+func NewLogger(config *config.Config) (*zap.SugaredLogger, error) {
+	return my_logger.New(my_logger.Config{ //nolint:wrapcheck
+		Level:            config.LogLevel,
+		Encoding:         config.LogEncoding,
+		Sampling:         config.LogSampling,
+		Caller:           config.LogCaller,
+		OutputPaths:      strings.Split(config.OutputLogPath, " "),
+		ErrorOutputPaths: strings.Split(config.ErrorOutputLogPath, " "),
+	})
+}
+
+// migrate applies pending migrations if autoMigrate is set, otherwise only checks that schema can be used.
+func migrate(ctx context.Context, pool *pgxpool.Pool, autoMigrate bool) error {
+	schemaMigrator, err := migrator.NewEmbeddedMigrator(pool)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	logger := my_logger.FromContext(ctx)
+
+	if autoMigrate {
+		applied, err := schemaMigrator.Up(ctx)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		logger.Infof("Schema is up to date, applied %d migrations", applied)
+
+		return nil
+	}
+
+	status, err := schemaMigrator.Check(ctx)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if len(status.Pending) != 0 {
+		logger.Warnf("Automatic migrations are disabled, %d migrations are pending", len(status.Pending))
+	}
+
+	return nil
+}
+
+// cleanupSessions deletes expired sessions of users every sessionsCleanupPeriod until ctx is done.
+func cleanupSessions(ctx context.Context, userService *userusecases.UserService) {
+	ticker := time.NewTicker(sessionsCleanupPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		deleted, err := userService.DeleteExpiredSessions(ctx)
+		if err != nil {
+			my_logger.FromContext(ctx).Errorf("in cleanupSessions: %+v", err)
+
+			continue
+		}
+
+		if deleted != 0 {
+			my_logger.FromContext(ctx).Infof("Deleted %d expired sessions", deleted)
+		}
+	}
+}
+
+// cleanupRateLimits forgets idle clients of limiter every rateLimitsCleanupPeriod until ctx is done.
+func cleanupRateLimits(ctx context.Context, limiter *ratelimit.Limiter) {
+	ticker := time.NewTicker(rateLimitsCleanupPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		limiter.Cleanup()
+	}
+}
+
+// Ready reports whether server accepts new requests.
+func (s *Server) Ready() bool {
+	return s.ready.Load()
+}
+
+// goWorker runs background worker which is stopped by canceling ctx before http server shutdown.
+func (s *Server) goWorker(worker func(ctx context.Context)) {
+	s.workers.Add(1)
+
+	go func() {
+		defer s.workers.Done()
 
-	return s.httpServer.ListenAndServe() //nolint:wrapcheck
+		worker(s.workersCtx)
+	}()
 }
 
+func (s *Server) shutdownWithTimeout(timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return s.Shutdown(ctx)
+}
+
+// Shutdown marks server as not ready, waits shutdownDelay for balancers to notice it and then stops
+// background workers, drains http server, closes pool of connections and flushes logger.
 func (s *Server) Shutdown(ctx context.Context) error {
-	return s.httpServer.Shutdown(ctx) //nolint:wrapcheck
+	s.ready.Store(false)
+
+	var errs []error
+
+	if s.httpServer != nil && s.shutdownDelay > 0 {
+		select {
+		case <-time.After(s.shutdownDelay):
+		case <-ctx.Done():
+		}
+	}
+
+	if s.cancelWorkers != nil {
+		s.cancelWorkers()
+	}
+
+	workersDone := make(chan struct{})
+
+	go func() {
+		s.workers.Wait()
+		close(workersDone)
+	}()
+
+	select {
+	case <-workersDone:
+	case <-ctx.Done():
+		errs = append(errs, fmt.Errorf("stop background workers: %w", ctx.Err()))
+	}
+
+	if s.httpServer != nil {
+		if err := s.httpServer.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("shutdown http server: %w", err))
+		}
+	}
+
+	if s.pool != nil {
+		s.pool.Close()
+	}
+
+	if s.logger != nil {
+		if err := errors.Join(errs...); err != nil {
+			s.logger.Errorf("in Shutdown: %+v", err)
+		} else {
+			s.logger.Infof("Server stopped")
+		}
+
+		// syncing of stdout and stderr fails with EINVAL or ENOTTY, it isn't a failure of shutdown
+		if err := s.logger.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) && !errors.Is(err, syscall.ENOTTY) {
+			errs = append(errs, fmt.Errorf("sync logger: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
 }
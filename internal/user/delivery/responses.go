@@ -0,0 +1,35 @@
+package delivery
+
+import "github.com/SanExpett/auto-catalog/pkg/models"
+
+const (
+	ResponseSuccessfulLogout = "logged_out"
+)
+
+type UserResponse struct {
+	Status int          `json:"status"`
+	Body   *models.User `json:"body"`
+}
+
+func NewUserResponse(status int, body *models.User) *UserResponse {
+	return &UserResponse{
+		Status: status,
+		Body:   body,
+	}
+}
+
+type CSRFTokenBody struct {
+	CSRFToken string `json:"csrf_token"`
+}
+
+type CSRFTokenResponse struct {
+	Status int           `json:"status"`
+	Body   CSRFTokenBody `json:"body"`
+}
+
+func NewCSRFTokenResponse(status int, csrfToken string) *CSRFTokenResponse {
+	return &CSRFTokenResponse{
+		Status: status,
+		Body:   CSRFTokenBody{CSRFToken: csrfToken},
+	}
+}
@@ -0,0 +1,183 @@
+package delivery
+
+import (
+	"context"
+	"github.com/SanExpett/auto-catalog/internal/server/delivery"
+	"github.com/SanExpett/auto-catalog/internal/user/usecases"
+	"github.com/SanExpett/auto-catalog/pkg/models"
+	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
+	"github.com/SanExpett/auto-catalog/pkg/my_logger"
+	"io"
+	"net/http"
+)
+
+var _ IUserService = (*usecases.UserService)(nil)
+
+type IUserService interface {
+	Register(ctx context.Context, r io.Reader) (*models.User, error)
+	Login(ctx context.Context, r io.Reader) (*models.User, *models.Session, error)
+	Logout(ctx context.Context, token string) error
+	AuthenticateSession(ctx context.Context, token string) (*models.User, error)
+}
+
+type UserHandler struct {
+	service      IUserService
+	secureCookie bool
+}
+
+func NewUserHandler(userService IUserService, secureCookie bool) (*UserHandler, error) {
+	return &UserHandler{
+		service:      userService,
+		secureCookie: secureCookie,
+	}, nil
+}
+
+// RegisterHandler creates user by json {"email", "password"}, password must be 8-72 bytes long.
+//
+//	@Summary    register user
+//	@Tags Auth
+//	@Accept      json
+//	@Produce    json,application/problem+json
+//	@Param      User  body models.PreUser true  "email and password of user"
+//	@Success    200  {object} UserResponse
+//	@Failure    400  {object} delivery.Problem "Bad Request"
+//	@Failure    403  {object} delivery.Problem "Forbidden"
+//	@Failure    405  {object} delivery.Problem "Method Not Allowed"
+//	@Failure    409  {object} delivery.Problem "Conflict"
+//	@Failure    422  {object} delivery.Problem "Unprocessable Entity"
+//	@Failure    429  {object} delivery.Problem "Too Many Requests"
+//	@Failure    500  {object} delivery.Problem "Internal Server Error"
+//	@Failure    503  {object} delivery.Problem "Service Unavailable"
+//	@Router      /v2/auth/register [post]
+func (u *UserHandler) RegisterHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := my_logger.FromContext(ctx)
+
+	user, err := u.service.Register(ctx, r.Body)
+	if err != nil {
+		delivery.HandleErr(w, r, logger, err)
+
+		return
+	}
+
+	delivery.SendOkResponse(w, r, logger, NewUserResponse(delivery.StatusResponseSuccessful, user))
+	logger.Infof("in RegisterHandler: registered user id=%d", user.ID)
+}
+
+// LoginHandler checks email and password and sets token of new session in HttpOnly cookie access_token.
+// Anti-CSRF token of session is sent in header X-CSRF-Token.
+//
+//	@Summary    log in
+//	@Tags Auth
+//	@Accept      json
+//	@Produce    json,application/problem+json
+//	@Param      User  body models.PreUser true  "email and password of user"
+//	@Success    200  {object} UserResponse
+//	@Failure    400  {object} delivery.Problem "Bad Request"
+//	@Failure    401  {object} delivery.Problem "Unauthorized"
+//	@Failure    403  {object} delivery.Problem "Forbidden"
+//	@Failure    405  {object} delivery.Problem "Method Not Allowed"
+//	@Failure    422  {object} delivery.Problem "Unprocessable Entity"
+//	@Failure    429  {object} delivery.Problem "Too Many Requests"
+//	@Failure    500  {object} delivery.Problem "Internal Server Error"
+//	@Failure    503  {object} delivery.Problem "Service Unavailable"
+//	@Router      /v2/auth/login [post]
+func (u *UserHandler) LoginHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := my_logger.FromContext(ctx)
+
+	user, session, err := u.service.Login(ctx, r.Body)
+	if err != nil {
+		delivery.HandleErr(w, r, logger, err)
+
+		return
+	}
+
+	http.SetCookie(w, delivery.NewAuthCookie(session.Token, session.ExpiresAt, u.secureCookie))
+	w.Header().Set(delivery.HeaderCSRFToken, delivery.CSRFToken(session.Token))
+	delivery.SendOkResponse(w, r, logger, NewUserResponse(delivery.StatusResponseSuccessful, user))
+	logger.Infof("in LoginHandler: user id=%d started session id=%d", user.ID, session.ID)
+}
+
+// LogoutHandler ends session of cookie access_token and removes cookie.
+//
+//	@Summary    log out
+//	@Tags Auth
+//	@Produce    json,application/problem+json
+//	@Success    200  {object} delivery.Response
+//	@Failure    403  {object} delivery.Problem "Forbidden"
+//	@Failure    405  {object} delivery.Problem "Method Not Allowed"
+//	@Failure    429  {object} delivery.Problem "Too Many Requests"
+//	@Failure    500  {object} delivery.Problem "Internal Server Error"
+//	@Failure    503  {object} delivery.Problem "Service Unavailable"
+//	@Router      /v2/auth/logout [post]
+func (u *UserHandler) LogoutHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := my_logger.FromContext(ctx)
+
+	if cookie, err := r.Cookie(delivery.CookieAuthName); err == nil && cookie.Value != "" {
+		if err := u.service.Logout(ctx, cookie.Value); err != nil {
+			delivery.HandleErr(w, r, logger, err)
+
+			return
+		}
+	}
+
+	http.SetCookie(w, delivery.ExpiredAuthCookie(u.secureCookie))
+	delivery.SendOkResponse(w, r, logger,
+		delivery.NewResponse(delivery.StatusResponseSuccessful,
+			myerrors.Message(delivery.LangFromContext(ctx), ResponseSuccessfulLogout)))
+}
+
+// MeHandler sends user logged in by cookie access_token.
+//
+//	@Summary    get current user
+//	@Tags Auth
+//	@Produce    json,application/problem+json
+//	@Success    200  {object} UserResponse
+//	@Failure    401  {object} delivery.Problem "Unauthorized"
+//	@Failure    405  {object} delivery.Problem "Method Not Allowed"
+//	@Failure    429  {object} delivery.Problem "Too Many Requests"
+//	@Failure    500  {object} delivery.Problem "Internal Server Error"
+//	@Failure    503  {object} delivery.Problem "Service Unavailable"
+//	@Router      /v2/auth/me [get]
+func (u *UserHandler) MeHandler(w http.ResponseWriter, r *http.Request) {
+	logger := my_logger.FromContext(r.Context())
+
+	user := delivery.UserFromContext(r.Context())
+	if user == nil {
+		delivery.HandleErr(w, r, logger, delivery.ErrUnauthorized)
+
+		return
+	}
+
+	delivery.SendOkResponse(w, r, logger, NewUserResponse(delivery.StatusResponseSuccessful, user))
+}
+
+// CSRFTokenHandler sends anti-CSRF token of session, it must be sent in header X-CSRF-Token with
+// POST, PUT, PATCH and DELETE requests authenticated by cookie access_token.
+//
+//	@Summary    get anti-CSRF token
+//	@Tags Auth
+//	@Produce    json,application/problem+json
+//	@Success    200  {object} CSRFTokenResponse
+//	@Failure    401  {object} delivery.Problem "Unauthorized"
+//	@Failure    405  {object} delivery.Problem "Method Not Allowed"
+//	@Failure    429  {object} delivery.Problem "Too Many Requests"
+//	@Failure    500  {object} delivery.Problem "Internal Server Error"
+//	@Failure    503  {object} delivery.Problem "Service Unavailable"
+//	@Router      /v2/auth/csrf [get]
+func (u *UserHandler) CSRFTokenHandler(w http.ResponseWriter, r *http.Request) {
+	logger := my_logger.FromContext(r.Context())
+
+	cookie, err := r.Cookie(delivery.CookieAuthName)
+	if err != nil || delivery.UserFromContext(r.Context()) == nil {
+		delivery.HandleErr(w, r, logger, delivery.ErrUnauthorized)
+
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	delivery.SendOkResponse(w, r, logger,
+		NewCSRFTokenResponse(delivery.StatusResponseSuccessful, delivery.CSRFToken(cookie.Value)))
+}
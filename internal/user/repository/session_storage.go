@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/SanExpett/auto-catalog/pkg/metrics"
+	"github.com/SanExpett/auto-catalog/pkg/models"
+	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
+	"github.com/SanExpett/auto-catalog/pkg/my_logger"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+var (
+	ErrSessionNotFound = myerrors.NewUnauthorizedError("session_invalid")
+)
+
+const (
+	// lastSeenPrecision limits writes of last activity time to one per session in this period
+	lastSeenPrecision = time.Minute
+)
+
+type SessionStorage struct {
+	pool *pgxpool.Pool
+}
+
+func NewSessionStorage(pool *pgxpool.Pool) (*SessionStorage, error) {
+	return &SessionStorage{pool: pool}, nil
+}
+
+func (s *SessionStorage) AddSession(ctx context.Context, userID uint64, tokenHash []byte, expiresAt time.Time,
+) (*models.Session, error) {
+	defer metrics.ObserveQuery("session", "AddSession", time.Now())
+
+	SQLInsertSession := `INSERT INTO public."session"(user_id, token_hash, expires_at) VALUES($1, $2, $3)
+		RETURNING id, created_at, last_seen_at`
+
+	session := &models.Session{UserID: userID, ExpiresAt: expiresAt} //nolint:exhaustruct
+
+	err := s.pool.QueryRow(ctx, SQLInsertSession, userID, tokenHash, expiresAt).
+		Scan(&session.ID, &session.CreatedAt, &session.LastSeenAt)
+	if err != nil {
+		my_logger.FromContext(ctx).Errorf("error with UserId=%d: %+v", userID, err)
+
+		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	return session, nil
+}
+
+// GetSessionUser returns session with hash of token tokenHash which is active at least since activeSince,
+// and its user.
+func (s *SessionStorage) GetSessionUser(ctx context.Context, tokenHash []byte, activeSince time.Time,
+) (*models.Session, *models.User, error) {
+	defer metrics.ObserveQuery("session", "GetSessionUser", time.Now())
+
+	SQLSelectSession := `SELECT s.id, s.user_id, s.created_at, s.last_seen_at, s.expires_at, u.email, u.person_id,
+		u.created_at
+		FROM public."session" s JOIN public."user" u ON u.id = s.user_id
+		WHERE s.token_hash=$1 AND s.expires_at > NOW() AND s.last_seen_at > $2`
+
+	session := &models.Session{} //nolint:exhaustruct
+	user := &models.User{}       //nolint:exhaustruct
+
+	err := s.pool.QueryRow(ctx, SQLSelectSession, tokenHash, activeSince).Scan(&session.ID, &session.UserID,
+		&session.CreatedAt, &session.LastSeenAt, &session.ExpiresAt, &user.Email, &user.PersonID, &user.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, fmt.Errorf(myerrors.ErrTemplate, ErrSessionNotFound)
+		}
+
+		my_logger.FromContext(ctx).Errorln(err)
+
+		return nil, nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	user.ID = session.UserID
+
+	return session, user, nil
+}
+
+// TouchSession records activity in session, time is updated not more often than lastSeenPrecision.
+func (s *SessionStorage) TouchSession(ctx context.Context, sessionID uint64) error {
+	defer metrics.ObserveQuery("session", "TouchSession", time.Now())
+
+	SQLUpdateLastSeenAt := `UPDATE public."session" SET last_seen_at=NOW()
+		WHERE id=$1 AND last_seen_at < NOW() - $2::interval`
+
+	if _, err := s.pool.Exec(ctx, SQLUpdateLastSeenAt, sessionID, lastSeenPrecision); err != nil {
+		my_logger.FromContext(ctx).Errorf("error with SessionId=%d: %+v", sessionID, err)
+
+		return fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	return nil
+}
+
+func (s *SessionStorage) DeleteSession(ctx context.Context, tokenHash []byte) error {
+	defer metrics.ObserveQuery("session", "DeleteSession", time.Now())
+
+	SQLDeleteSession := `DELETE FROM public."session" WHERE token_hash=$1`
+
+	if _, err := s.pool.Exec(ctx, SQLDeleteSession, tokenHash); err != nil {
+		my_logger.FromContext(ctx).Errorln(err)
+
+		return fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	return nil
+}
+
+// DeleteExpiredSessions deletes sessions which expired or weren't active since activeSince.
+func (s *SessionStorage) DeleteExpiredSessions(ctx context.Context, activeSince time.Time) (int64, error) {
+	defer metrics.ObserveQuery("session", "DeleteExpiredSessions", time.Now())
+
+	SQLDeleteExpiredSessions := `DELETE FROM public."session" WHERE expires_at <= NOW() OR last_seen_at <= $1`
+
+	result, err := s.pool.Exec(ctx, SQLDeleteExpiredSessions, activeSince)
+	if err != nil {
+		my_logger.FromContext(ctx).Errorln(err)
+
+		return 0, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/SanExpett/auto-catalog/internal/server/repository"
+	"github.com/SanExpett/auto-catalog/pkg/metrics"
+	"github.com/SanExpett/auto-catalog/pkg/models"
+	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
+	"github.com/SanExpett/auto-catalog/pkg/my_logger"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"time"
+)
+
+var (
+	ErrUserNotFound = myerrors.NewNotFoundError("user_not_found")
+
+	// UserConstraints are constraints of table user, memory storage checks them too
+	UserConstraints = map[string]repository.Constraint{ //nolint:gochecknoglobals
+		"user_email_key":      {Field: "email", Key: "email_already_registered"},
+		"user_email_check":    {Field: "email", Key: "field_required"},
+		"max_len_email":       {Field: "email", Key: "email_too_long"},
+		"user_person_id_key":  {Field: "person_id", Key: "person_already_linked"},
+		"user_person_id_fkey": {Field: "person_id", Key: "owner_not_exists"},
+	}
+)
+
+type UserStorage struct {
+	pool *pgxpool.Pool
+}
+
+func NewUserStorage(pool *pgxpool.Pool) (*UserStorage, error) {
+	return &UserStorage{pool: pool}, nil
+}
+
+func (u *UserStorage) AddUser(ctx context.Context, email string, passwordHash string) (*models.User, error) {
+	defer metrics.ObserveQuery("user", "AddUser", time.Now())
+
+	SQLInsertUser := `INSERT INTO public."user"(email, password_hash) VALUES($1, $2) RETURNING id, created_at`
+
+	user := &models.User{Email: email} //nolint:exhaustruct
+
+	if err := u.pool.QueryRow(ctx, SQLInsertUser, email, passwordHash).Scan(&user.ID, &user.CreatedAt); err != nil {
+		my_logger.FromContext(ctx).Errorln(err)
+
+		return nil, fmt.Errorf(myerrors.ErrTemplate, repository.MapPgError(err, UserConstraints))
+	}
+
+	return user, nil
+}
+
+// GetUserByEmail returns user and hash of user password.
+func (u *UserStorage) GetUserByEmail(ctx context.Context, email string) (*models.User, string, error) {
+	defer metrics.ObserveQuery("user", "GetUserByEmail", time.Now())
+
+	SQLSelectUser := `SELECT id, email, person_id, password_hash, created_at FROM public."user" WHERE email=$1`
+
+	user := &models.User{} //nolint:exhaustruct
+
+	var passwordHash string
+
+	err := u.pool.QueryRow(ctx, SQLSelectUser, email).Scan(&user.ID, &user.Email, &user.PersonID, &passwordHash,
+		&user.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, "", fmt.Errorf(myerrors.ErrTemplate, ErrUserNotFound)
+		}
+
+		my_logger.FromContext(ctx).Errorln(err)
+
+		return nil, "", fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	return user, passwordHash, nil
+}
+
+// LinkPerson links user with email to person, nil personID removes link.
+func (u *UserStorage) LinkPerson(ctx context.Context, email string, personID *uint64) error {
+	defer metrics.ObserveQuery("user", "LinkPerson", time.Now())
+
+	SQLUpdatePersonID := `UPDATE public."user" SET person_id=$2 WHERE email=$1`
+
+	result, err := u.pool.Exec(ctx, SQLUpdatePersonID, email, personID)
+	if err != nil {
+		my_logger.FromContext(ctx).Errorln(err)
+
+		return fmt.Errorf(myerrors.ErrTemplate, repository.MapPgError(err, UserConstraints))
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf(myerrors.ErrTemplate, ErrUserNotFound)
+	}
+
+	return nil
+}
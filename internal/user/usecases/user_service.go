@@ -0,0 +1,187 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	userrepo "github.com/SanExpett/auto-catalog/internal/user/repository"
+	"github.com/SanExpett/auto-catalog/pkg/debounce"
+	"github.com/SanExpett/auto-catalog/pkg/models"
+	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
+	"github.com/SanExpett/auto-catalog/pkg/my_logger"
+	"golang.org/x/crypto/bcrypt"
+	"io"
+	"strings"
+	"time"
+)
+
+var (
+	_ IUserStorage    = (*userrepo.UserStorage)(nil)
+	_ ISessionStorage = (*userrepo.SessionStorage)(nil)
+)
+
+var (
+	ErrInvalidCredentials = myerrors.NewUnauthorizedError("invalid_credentials")
+	ErrInvalidSession     = userrepo.ErrSessionNotFound
+)
+
+const (
+	sessionTokenBytes = 32
+	// touchPeriod limits writes of last activity time to one per session in this period, idle timeout
+	// doesn't need better precision
+	touchPeriod = time.Minute
+)
+
+type IUserStorage interface {
+	AddUser(ctx context.Context, email string, passwordHash string) (*models.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*models.User, string, error)
+	LinkPerson(ctx context.Context, email string, personID *uint64) error
+}
+
+type ISessionStorage interface {
+	AddSession(ctx context.Context, userID uint64, tokenHash []byte, expiresAt time.Time) (*models.Session, error)
+	GetSessionUser(ctx context.Context, tokenHash []byte, activeSince time.Time) (*models.Session, *models.User, error)
+	TouchSession(ctx context.Context, sessionID uint64) error
+	DeleteSession(ctx context.Context, tokenHash []byte) error
+	DeleteExpiredSessions(ctx context.Context, activeSince time.Time) (int64, error)
+}
+
+type UserService struct {
+	userStorage    IUserStorage
+	sessionStorage ISessionStorage
+	// idleTimeout ends session without activity, maxAge ends session anyway
+	idleTimeout time.Duration
+	maxAge      time.Duration
+	// dummyHash is compared with password of unknown user, so time of login doesn't tell whether user exists
+	dummyHash []byte
+	touches   *debounce.Debouncer
+}
+
+func NewUserService(userStorage IUserStorage, sessionStorage ISessionStorage, idleTimeout time.Duration,
+	maxAge time.Duration,
+) (*UserService, error) {
+	dummyHash, err := bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	return &UserService{
+		userStorage:    userStorage,
+		sessionStorage: sessionStorage,
+		idleTimeout:    idleTimeout,
+		maxAge:         maxAge,
+		dummyHash:      dummyHash,
+		touches:        debounce.New(touchPeriod),
+	}, nil
+}
+
+func (u *UserService) Register(ctx context.Context, r io.Reader) (*models.User, error) {
+	preUser, err := ValidatePreUser(ctx, r)
+	if err != nil {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(preUser.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	user, err := u.userStorage.AddUser(ctx, preUser.Email, string(passwordHash))
+	if err != nil {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	return user, nil
+}
+
+// Login checks credentials and starts new session, its Token is sent to client.
+func (u *UserService) Login(ctx context.Context, r io.Reader) (*models.User, *models.Session, error) {
+	preUser, err := ValidatePreUser(ctx, r)
+	if err != nil {
+		return nil, nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	user, passwordHash, err := u.userStorage.GetUserByEmail(ctx, preUser.Email)
+	if err != nil {
+		if !errors.Is(err, userrepo.ErrUserNotFound) {
+			return nil, nil, fmt.Errorf(myerrors.ErrTemplate, err)
+		}
+
+		_ = bcrypt.CompareHashAndPassword(u.dummyHash, []byte(preUser.Password))
+
+		return nil, nil, fmt.Errorf(myerrors.ErrTemplate, ErrInvalidCredentials)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(preUser.Password)); err != nil {
+		return nil, nil, fmt.Errorf(myerrors.ErrTemplate, ErrInvalidCredentials)
+	}
+
+	secret := make([]byte, sessionTokenBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(secret)
+
+	session, err := u.sessionStorage.AddSession(ctx, user.ID, hashToken(token), time.Now().Add(u.maxAge))
+	if err != nil {
+		return nil, nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	session.Token = token
+
+	return user, session, nil
+}
+
+func (u *UserService) Logout(ctx context.Context, token string) error {
+	if err := u.sessionStorage.DeleteSession(ctx, hashToken(token)); err != nil {
+		return fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	return nil
+}
+
+// AuthenticateSession returns user of active session with token or ErrInvalidSession.
+func (u *UserService) AuthenticateSession(ctx context.Context, token string) (*models.User, error) {
+	session, user, err := u.sessionStorage.GetSessionUser(ctx, hashToken(token), time.Now().Add(-u.idleTimeout))
+	if err != nil {
+		return nil, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	// request mustn't fail or wait because of statistics, session just expires a bit earlier
+	if u.touches.Allow(session.ID) {
+		if err := u.sessionStorage.TouchSession(ctx, session.ID); err != nil {
+			my_logger.FromContext(ctx).Warnf("in AuthenticateSession: %+v", err)
+		}
+	}
+
+	return user, nil
+}
+
+// LinkPerson makes user with email owner of person personID, nil personID gives user full access back.
+func (u *UserService) LinkPerson(ctx context.Context, email string, personID *uint64) error {
+	if err := u.userStorage.LinkPerson(ctx, strings.ToLower(strings.TrimSpace(email)), personID); err != nil {
+		return fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	return nil
+}
+
+// DeleteExpiredSessions removes sessions which can't be used anymore.
+func (u *UserService) DeleteExpiredSessions(ctx context.Context) (int64, error) {
+	deleted, err := u.sessionStorage.DeleteExpiredSessions(ctx, time.Now().Add(-u.idleTimeout))
+	if err != nil {
+		return 0, fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	return deleted, nil
+}
+
+func hashToken(token string) []byte {
+	hash := sha256.Sum256([]byte(token))
+
+	return hash[:]
+}
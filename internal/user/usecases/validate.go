@@ -0,0 +1,39 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/SanExpett/auto-catalog/pkg/models"
+	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
+	"github.com/SanExpett/auto-catalog/pkg/my_logger"
+	"github.com/SanExpett/auto-catalog/pkg/utils"
+	"io"
+)
+
+var (
+	ErrDecodePreUser = myerrors.NewError("user_decode")
+)
+
+func ValidatePreUser(ctx context.Context, r io.Reader) (*models.PreUser, error) {
+	decoder := json.NewDecoder(r)
+	preUser := &models.PreUser{}
+	if err := decoder.Decode(preUser); err != nil {
+		my_logger.FromContext(ctx).Errorln(err)
+
+		if fieldError, ok := utils.FieldErrorFromJSON(err); ok {
+			return nil, myerrors.NewValidationErrors([]myerrors.FieldError{fieldError})
+		}
+
+		return nil, fmt.Errorf(myerrors.ErrTemplate, ErrDecodePreUser)
+	}
+
+	preUser.Trim()
+
+	fieldErrors := utils.ValidateStruct(preUser, false)
+	if len(fieldErrors) != 0 {
+		return nil, myerrors.NewValidationErrors(fieldErrors)
+	}
+
+	return preUser, nil
+}
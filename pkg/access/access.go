@@ -0,0 +1,41 @@
+// Package access describes restrictions of caller which services apply to every operation, so they can't be
+// bypassed by any endpoint.
+package access
+
+import (
+	"context"
+	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
+)
+
+var (
+	// ErrOwnerForbidden is returned to owner for operations with own data which owners can't do
+	ErrOwnerForbidden = myerrors.NewForbiddenError("owner_forbidden")
+)
+
+type (
+	ownerKey        struct{}
+	unrestrictedKey struct{}
+)
+
+// WithOwner restricts caller to person personID and cars of that person.
+func WithOwner(ctx context.Context, personID uint64) context.Context {
+	return context.WithValue(ctx, ownerKey{}, personID)
+}
+
+// WithUnrestricted lets caller access data of all people. Only API keys are unrestricted, their scopes limit them.
+func WithUnrestricted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, unrestrictedKey{}, true)
+}
+
+// OwnerFromContext returns id of person to which caller is restricted, false means caller isn't restricted.
+// Callers are restricted unless context is marked by WithUnrestricted, caller not linked to person gets id 0
+// and owns nothing, ids of people start from 1.
+func OwnerFromContext(ctx context.Context) (uint64, bool) {
+	if unrestricted, _ := ctx.Value(unrestrictedKey{}).(bool); unrestricted {
+		return 0, false
+	}
+
+	personID, _ := ctx.Value(ownerKey{}).(uint64)
+
+	return personID, true
+}
@@ -0,0 +1,212 @@
+// Package cache holds bounded in-memory cache of loaded values with expiration.
+package cache
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"github.com/SanExpett/auto-catalog/pkg/metrics"
+	"golang.org/x/sync/singleflight"
+	"sync"
+	"time"
+)
+
+const (
+	resultHit  = "hit"
+	resultMiss = "miss"
+)
+
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+// LRU keeps at most capacity values for ttl and evicts least recently used ones. Concurrent loads of
+// the same missing key are collapsed into one.
+type LRU[K comparable, V any] struct {
+	name     string
+	capacity int
+	ttl      time.Duration
+
+	mu    sync.Mutex
+	items map[K]*list.Element
+	order *list.List
+	loads singleflight.Group
+	now   func() time.Time
+	// version grows on every invalidation. Value loaded since version isn't stored if its key was deleted
+	// later (deleted of its flight) or all keys were (purged)
+	version uint64
+	purged  uint64
+	flights map[K]*flight
+}
+
+// flight counts loads of key in progress and remembers version of last deletion of key during them.
+type flight struct {
+	loads   int
+	deleted uint64
+}
+
+// NewLRU returns cache named name for metrics.
+func NewLRU[K comparable, V any](name string, capacity int, ttl time.Duration) *LRU[K, V] {
+	return &LRU[K, V]{
+		name:     name,
+		capacity: capacity,
+		ttl:      ttl,
+		mu:       sync.Mutex{},
+		items:    make(map[K]*list.Element),
+		order:    list.New(),
+		loads:    singleflight.Group{},
+		now:      time.Now,
+		version:  0,
+		purged:   0,
+		flights:  make(map[K]*flight),
+	}
+}
+
+// GetOrLoad returns cached value of key or value returned by load, which is cached if it succeeds.
+// Errors are not cached. Load shared by concurrent callers isn't canceled with context of any of them,
+// every caller stops waiting for it when its own ctx is done.
+func (c *LRU[K, V]) GetOrLoad(ctx context.Context, key K, load func(ctx context.Context) (V, error)) (V, error) {
+	if value, ok := c.get(key); ok {
+		metrics.IncCache(c.name, resultHit)
+
+		return value, nil
+	}
+
+	metrics.IncCache(c.name, resultMiss)
+
+	loadCtx := context.WithoutCancel(ctx)
+
+	results := c.loads.DoChan(fmt.Sprint(key), func() (any, error) {
+		version := c.startLoad(key)
+
+		value, err := load(loadCtx)
+		c.finishLoad(key, value, version, err == nil)
+
+		return value, err
+	})
+
+	select {
+	case result := <-results:
+		value, _ := result.Val.(V)
+
+		return value, result.Err //nolint:wrapcheck
+	case <-ctx.Done():
+		var zero V
+
+		return zero, ctx.Err() //nolint:wrapcheck
+	}
+}
+
+// Delete invalidates value of key.
+func (c *LRU[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.version++
+	c.loads.Forget(fmt.Sprint(key))
+
+	if keyFlight, ok := c.flights[key]; ok {
+		keyFlight.deleted = c.version
+	}
+
+	if element, ok := c.items[key]; ok {
+		c.remove(element)
+	}
+}
+
+// DeleteFunc invalidates all values for which del returns true.
+func (c *LRU[K, V]) DeleteFunc(del func(key K, value V) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.version++
+	c.purged = c.version
+
+	for key, element := range c.items {
+		if del(key, element.Value.(*entry[K, V]).value) { //nolint:forcetypeassert
+			c.loads.Forget(fmt.Sprint(key))
+			c.remove(element)
+		}
+	}
+}
+
+func (c *LRU[K, V]) get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		var zero V
+
+		return zero, false
+	}
+
+	item := element.Value.(*entry[K, V]) //nolint:forcetypeassert
+	if !c.now().Before(item.expiresAt) {
+		c.remove(element)
+
+		var zero V
+
+		return zero, false
+	}
+
+	c.order.MoveToFront(element)
+
+	return item.value, true
+}
+
+// startLoad registers load of key and returns version it starts at.
+func (c *LRU[K, V]) startLoad(key K) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keyFlight, ok := c.flights[key]
+	if !ok {
+		keyFlight = &flight{loads: 0, deleted: 0}
+		c.flights[key] = keyFlight
+	}
+
+	keyFlight.loads++
+
+	return c.version
+}
+
+// finishLoad stores value of successful load started at version unless key was invalidated meanwhile.
+func (c *LRU[K, V]) finishLoad(key K, value V, version uint64, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keyFlight := c.flights[key]
+	invalidated := keyFlight.deleted > version || c.purged > version
+
+	keyFlight.loads--
+	if keyFlight.loads == 0 {
+		delete(c.flights, key)
+	}
+
+	if !ok || invalidated {
+		return
+	}
+
+	item := &entry[K, V]{key: key, value: value, expiresAt: c.now().Add(c.ttl)}
+
+	if element, ok := c.items[key]; ok {
+		element.Value = item
+		c.order.MoveToFront(element)
+
+		return
+	}
+
+	c.items[key] = c.order.PushFront(item)
+
+	for c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+	}
+}
+
+func (c *LRU[K, V]) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.items, element.Value.(*entry[K, V]).key) //nolint:forcetypeassert
+}
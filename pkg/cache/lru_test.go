@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// loadDuring returns load which signals started, waits for release and returns value.
+func loadDuring(started chan<- struct{}, release <-chan struct{}, value int) func(ctx context.Context) (int, error) {
+	return func(context.Context) (int, error) {
+		started <- struct{}{}
+		<-release
+
+		return value, nil
+	}
+}
+
+// getWhileLoading starts GetOrLoad of key by load which returns value and calls during while load is in
+// progress, then it lets load finish.
+func getWhileLoading(t *testing.T, c *LRU[int, int], key int, value int, during func()) {
+	t.Helper()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		if _, err := c.GetOrLoad(context.Background(), key, loadDuring(started, release, value)); err != nil {
+			t.Errorf("GetOrLoad: %v", err)
+		}
+	}()
+
+	<-started
+	during()
+	close(release)
+	<-done
+}
+
+func TestDeleteDuringLoadDropsLoadedValue(t *testing.T) {
+	c := NewLRU[int, int]("test", 10, time.Minute)
+
+	getWhileLoading(t, c, 1, 1, func() { c.Delete(1) })
+
+	if value, ok := c.get(1); ok {
+		t.Fatalf("value %d loaded before Delete is cached", value)
+	}
+
+	if len(c.flights) != 0 {
+		t.Fatalf("%d finished loads are kept", len(c.flights))
+	}
+}
+
+func TestDeleteOfOtherKeyKeepsLoadedValue(t *testing.T) {
+	c := NewLRU[int, int]("test", 10, time.Minute)
+
+	getWhileLoading(t, c, 1, 1, func() { c.Delete(2) })
+
+	if value, ok := c.get(1); !ok || value != 1 {
+		t.Fatalf("value of key 1 isn't cached after Delete of key 2, got %d, %t", value, ok)
+	}
+}
+
+func TestDeleteFuncDuringLoadDropsLoadedValue(t *testing.T) {
+	c := NewLRU[int, int]("test", 10, time.Minute)
+
+	getWhileLoading(t, c, 1, 1, func() { c.DeleteFunc(func(int, int) bool { return false }) })
+
+	if value, ok := c.get(1); ok {
+		t.Fatalf("value %d loaded before DeleteFunc is cached", value)
+	}
+}
+
+func TestLoadAfterDeleteIsCached(t *testing.T) {
+	c := NewLRU[int, int]("test", 10, time.Minute)
+
+	c.Delete(1)
+	getWhileLoading(t, c, 1, 1, func() {})
+
+	if value, ok := c.get(1); !ok || value != 1 {
+		t.Fatalf("value loaded after Delete isn't cached, got %d, %t", value, ok)
+	}
+}
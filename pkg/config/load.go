@@ -0,0 +1,140 @@
+package config
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrRequired        = errors.New("required setting is not set")
+	ErrUnsupportedType = errors.New("unsupported type of setting")
+)
+
+// setting is field of Config described by tags.
+type setting struct {
+	name       string
+	field      reflect.Value
+	defaultVal string
+	hasDefault bool
+	required   bool
+	secret     bool
+}
+
+func settings(config *Config) []setting {
+	value := reflect.ValueOf(config).Elem()
+	result := make([]setting, 0, value.NumField())
+
+	for i := 0; i < value.NumField(); i++ {
+		structField := value.Type().Field(i)
+
+		name, ok := structField.Tag.Lookup("env")
+		if !ok {
+			continue
+		}
+
+		defaultVal, hasDefault := structField.Tag.Lookup("default")
+
+		result = append(result, setting{
+			name:       name,
+			field:      value.Field(i),
+			defaultVal: defaultVal,
+			hasDefault: hasDefault,
+			required:   structField.Tag.Get("required") == "true",
+			secret:     structField.Tag.Get("secret") == "true",
+		})
+	}
+
+	return result
+}
+
+// load fills config from src and returns problems of every setting.
+func load(config *Config, src *source) []error {
+	known := make(map[string]bool)
+	problems := append([]error(nil), src.problems...)
+
+	for _, setting := range settings(config) {
+		known[setting.name] = true
+
+		raw, ok, err := src.value(setting.name)
+		if err != nil {
+			problems = append(problems, err)
+
+			continue
+		}
+
+		if !ok {
+			if setting.required {
+				problems = append(problems, fmt.Errorf("%s: %w", setting.name, ErrRequired))
+
+				continue
+			}
+
+			raw = setting.defaultVal
+		}
+
+		if err := parse(setting.field, raw); err != nil {
+			problems = append(problems, fmt.Errorf("%s: %w", setting.name, err))
+		}
+	}
+
+	return append(problems, src.unknown(known)...)
+}
+
+func parse(field reflect.Value, raw string) error {
+	if unmarshaler, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return unmarshaler.UnmarshalText([]byte(raw)) //nolint:wrapcheck
+	}
+
+	if field.Type() == reflect.TypeOf(time.Duration(0)) {
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		field.SetInt(int64(duration))
+
+		return nil
+	}
+
+	switch field.Kind() { //nolint:exhaustive
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		field.SetBool(value)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		value, err := strconv.ParseInt(raw, 10, field.Type().Bits())
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		field.SetInt(value)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("%w: %s", ErrUnsupportedType, field.Type())
+		}
+
+		var items []string
+
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+
+		field.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedType, field.Type())
+	}
+
+	return nil
+}
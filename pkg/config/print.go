@@ -0,0 +1,56 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const redacted = "******"
+
+// Print writes effective config as NAME=value lines, values of secret settings are redacted.
+func (c *Config) Print(w io.Writer) error {
+	for _, setting := range settings(c) {
+		value := format(setting.field)
+		if setting.secret {
+			value = redact(value)
+		}
+
+		if _, err := fmt.Fprintf(w, "%s=%s\n", setting.name, value); err != nil {
+			return err //nolint:wrapcheck
+		}
+	}
+
+	return nil
+}
+
+func format(field reflect.Value) string {
+	switch value := field.Interface().(type) {
+	case time.Duration:
+		return value.String()
+	case []string:
+		return strings.Join(value, ",")
+	case fmt.Stringer:
+		return value.String()
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// redact keeps url of secret without password, as rest of it is useful for debugging.
+func redact(value string) string {
+	if value == "" {
+		return ""
+	}
+
+	if parsed, err := url.Parse(value); err == nil && parsed.User != nil {
+		if _, hasPassword := parsed.User.Password(); hasPassword {
+			return parsed.Redacted()
+		}
+	}
+
+	return redacted
+}
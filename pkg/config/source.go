@@ -0,0 +1,167 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	envConfigFile = "CONFIG_FILE"
+	suffixFile    = "_FILE"
+)
+
+var (
+	ErrUnknownSetting = errors.New("unknown setting")
+	ErrDotEnvLine     = errors.New("expected NAME=value")
+)
+
+// source finds raw values of settings. Problems of source itself are reported by problems.
+type source struct {
+	// overrides take precedence over environment and file
+	overrides map[string]string
+	file      map[string]string
+	lookup    func(name string) (string, bool)
+	readFile  func(name string) ([]byte, error)
+	problems  []error
+}
+
+func newEnvSource() *source {
+	src := &source{overrides: nil, file: nil, lookup: os.LookupEnv, readFile: os.ReadFile, problems: nil}
+
+	if path, ok := os.LookupEnv(envConfigFile); ok && path != "" {
+		file, err := readConfigFile(path)
+		if err != nil {
+			src.problems = append(src.problems, fmt.Errorf("%s: %w", envConfigFile, err))
+		}
+
+		src.file = file
+	}
+
+	return src
+}
+
+// value returns raw value of setting name and whether it is set.
+func (s *source) value(name string) (string, bool, error) {
+	if value, ok := s.overrides[name]; ok {
+		return value, true, nil
+	}
+
+	value, inEnv := s.lookup(name)
+	path, inSecretFile := s.lookup(name + suffixFile)
+
+	switch {
+	case inEnv && inSecretFile:
+		return "", false, fmt.Errorf("both %s and %s%s are set", name, name, suffixFile)
+	case inSecretFile:
+		content, err := s.readFile(path)
+		if err != nil {
+			return "", false, fmt.Errorf("%s%s: %w", name, suffixFile, err)
+		}
+
+		return strings.TrimRight(string(content), "\r\n"), true, nil
+	case inEnv:
+		return value, true, nil
+	}
+
+	value, inFile := s.file[name]
+
+	return value, inFile, nil
+}
+
+// unknown reports settings of config file which don't exist, as they are most likely typos.
+func (s *source) unknown(known map[string]bool) []error {
+	names := make([]string, 0, len(s.file))
+
+	for name := range s.file {
+		if !known[name] {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	problems := make([]error, 0, len(names))
+	for _, name := range names {
+		problems = append(problems, fmt.Errorf("%s: %w in config file", name, ErrUnknownSetting))
+	}
+
+	return problems
+}
+
+// readConfigFile reads yaml file if it has .yaml or .yml extension and .env file otherwise.
+func readConfigFile(path string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return parseYAML(content)
+	default:
+		return parseDotEnv(content)
+	}
+}
+
+// parseYAML reads flat mapping of settings, lists are joined by comma.
+func parseYAML(content []byte) (map[string]string, error) {
+	raw := make(map[string]any)
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	result := make(map[string]string, len(raw))
+
+	for name, value := range raw {
+		switch typed := value.(type) {
+		case nil:
+			result[strings.ToUpper(name)] = ""
+		case []any:
+			items := make([]string, 0, len(typed))
+			for _, item := range typed {
+				items = append(items, fmt.Sprint(item))
+			}
+
+			result[strings.ToUpper(name)] = strings.Join(items, ",")
+		default:
+			result[strings.ToUpper(name)] = fmt.Sprint(typed)
+		}
+	}
+
+	return result, nil
+}
+
+// parseDotEnv reads lines NAME=value, empty lines and lines starting with # are skipped.
+func parseDotEnv(content []byte) (map[string]string, error) {
+	result := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, ErrDotEnvLine)
+		}
+
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+
+		result[strings.TrimSpace(name)] = value
+	}
+
+	return result, scanner.Err() //nolint:wrapcheck
+}
@@ -0,0 +1,55 @@
+// Package debounce lets actions repeated by many requests, e.g. writes of last activity time, run not more
+// often than once per period for every key.
+package debounce
+
+import (
+	"sync"
+	"time"
+)
+
+// Debouncer remembers when action last ran for every key. Keys idle for period are forgotten, so it keeps
+// only keys active within period.
+type Debouncer struct {
+	period time.Duration
+
+	mu        sync.Mutex
+	last      map[uint64]time.Time
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func New(period time.Duration) *Debouncer {
+	return &Debouncer{
+		period:    period,
+		mu:        sync.Mutex{},
+		last:      make(map[uint64]time.Time),
+		lastSweep: time.Time{},
+		now:       time.Now,
+	}
+}
+
+// Allow reports whether action for key should run now. If it should, next one is allowed after period.
+func (d *Debouncer) Allow(key uint64) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+
+	if now.Sub(d.lastSweep) >= d.period {
+		for k, last := range d.last {
+			if now.Sub(last) >= d.period {
+				delete(d.last, k)
+			}
+		}
+
+		d.lastSweep = now
+	}
+
+	if last, ok := d.last[key]; ok && now.Sub(last) < d.period {
+		return false
+	}
+
+	d.last[key] = now
+
+	return true
+}
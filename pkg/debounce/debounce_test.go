@@ -0,0 +1,35 @@
+package debounce
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAllow(t *testing.T) {
+	now := time.Now()
+
+	d := New(time.Minute)
+	d.now = func() time.Time { return now }
+
+	if !d.Allow(1) {
+		t.Fatal("first action of key is not allowed")
+	}
+
+	if d.Allow(1) {
+		t.Fatal("action is allowed again within period")
+	}
+
+	if !d.Allow(2) {
+		t.Fatal("action of other key is not allowed")
+	}
+
+	now = now.Add(time.Minute)
+
+	if !d.Allow(1) {
+		t.Fatal("action is not allowed after period")
+	}
+
+	if len(d.last) != 1 {
+		t.Fatalf("idle keys are not forgotten, %d keys are kept", len(d.last))
+	}
+}
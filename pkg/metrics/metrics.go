@@ -0,0 +1,150 @@
+// Package metrics holds prometheus collectors of the service, they are exposed by Handler in text format.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	namespace = "auto_catalog"
+
+	// RouteUnmatched is route label of requests which didn't match any route of router.
+	RouteUnmatched = "unmatched"
+)
+
+var (
+	registry = prometheus.NewRegistry() //nolint:gochecknoglobals
+
+	httpRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{ //nolint:gochecknoglobals,exhaustruct
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Count of handled http requests by route pattern, method and status.",
+	}, []string{"route", "method", "status"})
+
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{ //nolint:gochecknoglobals,exhaustruct
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of handled http requests by route pattern, method and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	httpRequestsInFlight = prometheus.NewGauge(prometheus.GaugeOpts{ //nolint:gochecknoglobals,exhaustruct
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_in_flight",
+		Help:      "Count of http requests being handled now.",
+	})
+
+	httpRateLimitedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{ //nolint:gochecknoglobals,exhaustruct
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "rate_limited_total",
+		Help:      "Count of http requests rejected by rate limit by class of requests.",
+	}, []string{"class"})
+
+	httpRequestsShedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{ //nolint:gochecknoglobals,exhaustruct
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_shed_total",
+		Help:      "Count of http requests rejected because of too many requests in flight by reason.",
+	}, []string{"reason"})
+
+	repositoryQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{ //nolint:gochecknoglobals,exhaustruct
+		Namespace: namespace,
+		Subsystem: "repository",
+		Name:      "query_duration_seconds",
+		Help:      "Latency of repository methods by storage and method.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"storage", "method"})
+
+	cacheRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{ //nolint:gochecknoglobals,exhaustruct
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "requests_total",
+		Help:      "Count of reads of caches by cache and result: hit or miss.",
+	}, []string{"cache", "result"})
+
+	outboundRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{ //nolint:gochecknoglobals,exhaustruct
+		Namespace: namespace,
+		Subsystem: "outbound",
+		Name:      "requests_total",
+		Help:      "Count of requests to external APIs by api and result.",
+	}, []string{"api", "result"})
+
+	outboundRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{ //nolint:gochecknoglobals,exhaustruct
+		Namespace: namespace,
+		Subsystem: "outbound",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of requests to external APIs by api and result.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"api", "result"})
+)
+
+func init() { //nolint:gochecknoinits
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}), //nolint:exhaustruct
+		httpRequestsTotal,
+		httpRequestDuration,
+		httpRequestsInFlight,
+		httpRateLimitedTotal,
+		httpRequestsShedTotal,
+		repositoryQueryDuration,
+		cacheRequestsTotal,
+		outboundRequestsTotal,
+		outboundRequestDuration,
+	)
+}
+
+// Handler exposes all registered metrics in prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{}) //nolint:exhaustruct
+}
+
+// Register adds collector of other component, e.g. of pool of connections.
+func Register(collector prometheus.Collector) error {
+	return registry.Register(collector) //nolint:wrapcheck
+}
+
+// IncInFlight counts start of http request and returns function counting its end.
+func IncInFlight() func() {
+	httpRequestsInFlight.Inc()
+
+	return httpRequestsInFlight.Dec
+}
+
+func ObserveHTTPRequest(route string, method string, status int, duration time.Duration) {
+	statusStr := strconv.Itoa(status)
+
+	httpRequestsTotal.WithLabelValues(route, method, statusStr).Inc()
+	httpRequestDuration.WithLabelValues(route, method, statusStr).Observe(duration.Seconds())
+}
+
+// IncRateLimited counts request of class rejected by rate limit.
+func IncRateLimited(class string) {
+	httpRateLimitedTotal.WithLabelValues(class).Inc()
+}
+
+// IncShed counts request rejected by concurrency limit for reason.
+func IncShed(reason string) {
+	httpRequestsShedTotal.WithLabelValues(reason).Inc()
+}
+
+// IncCache counts read of cache with result hit or miss.
+func IncCache(cache string, result string) {
+	cacheRequestsTotal.WithLabelValues(cache, result).Inc()
+}
+
+// ObserveQuery records latency of repository method started at start. It is meant to be deferred:
+//
+//	defer metrics.ObserveQuery("car", "AddCar", time.Now())
+func ObserveQuery(storage string, method string, start time.Time) {
+	repositoryQueryDuration.WithLabelValues(storage, method).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+)
+
+const (
+	ResultSuccess     = "success"
+	ResultClientError = "client_error"
+	ResultServerError = "server_error"
+	ResultError       = "error"
+)
+
+// roundTripper counts results of requests to external api.
+type roundTripper struct {
+	api  string
+	next http.RoundTripper
+}
+
+// InstrumentRoundTripper records result and latency of every request sent through next to api.
+func InstrumentRoundTripper(api string, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &roundTripper{api: api, next: next}
+}
+
+func (t *roundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	start := time.Now()
+
+	response, err := t.next.RoundTrip(request)
+
+	result := ResultSuccess
+
+	switch {
+	case err != nil:
+		result = ResultError
+	case response.StatusCode >= http.StatusInternalServerError:
+		result = ResultServerError
+	case response.StatusCode >= http.StatusBadRequest:
+		result = ResultClientError
+	}
+
+	outboundRequestsTotal.WithLabelValues(t.api, result).Inc()
+	outboundRequestDuration.WithLabelValues(t.api, result).Observe(time.Since(start).Seconds())
+
+	return response, err //nolint:wrapcheck
+}
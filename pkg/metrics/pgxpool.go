@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PoolCollector exposes statistics of pool of connections to postgres.
+type PoolCollector struct {
+	pool *pgxpool.Pool
+
+	acquiredConns        *prometheus.Desc
+	idleConns            *prometheus.Desc
+	constructingConns    *prometheus.Desc
+	totalConns           *prometheus.Desc
+	maxConns             *prometheus.Desc
+	acquireCount         *prometheus.Desc
+	emptyAcquireCount    *prometheus.Desc
+	canceledAcquireCount *prometheus.Desc
+	acquireDuration      *prometheus.Desc
+}
+
+var _ prometheus.Collector = (*PoolCollector)(nil)
+
+func NewPoolCollector(pool *pgxpool.Pool) *PoolCollector {
+	desc := func(name string, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "pgxpool", name), help, nil, nil)
+	}
+
+	return &PoolCollector{
+		pool:                 pool,
+		acquiredConns:        desc("acquired_conns", "Count of connections currently acquired from pool."),
+		idleConns:            desc("idle_conns", "Count of idle connections in pool."),
+		constructingConns:    desc("constructing_conns", "Count of connections being established."),
+		totalConns:           desc("total_conns", "Count of all connections in pool."),
+		maxConns:             desc("max_conns", "Maximum size of pool."),
+		acquireCount:         desc("acquire_count_total", "Count of successful acquires of connection."),
+		emptyAcquireCount:    desc("empty_acquire_count_total", "Count of acquires which waited for free connection."),
+		canceledAcquireCount: desc("canceled_acquire_count_total", "Count of acquires canceled by context."),
+		acquireDuration: desc("acquire_wait_duration_seconds_total",
+			"Total time spent waiting for connection by successful acquires."),
+	}
+}
+
+func (c *PoolCollector) Describe(descs chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(c, descs)
+}
+
+func (c *PoolCollector) Collect(metrics chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+
+	metrics <- prometheus.MustNewConstMetric(c.acquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	metrics <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stat.IdleConns()))
+	metrics <- prometheus.MustNewConstMetric(c.constructingConns, prometheus.GaugeValue,
+		float64(stat.ConstructingConns()))
+	metrics <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stat.TotalConns()))
+	metrics <- prometheus.MustNewConstMetric(c.maxConns, prometheus.GaugeValue, float64(stat.MaxConns()))
+	metrics <- prometheus.MustNewConstMetric(c.acquireCount, prometheus.CounterValue, float64(stat.AcquireCount()))
+	metrics <- prometheus.MustNewConstMetric(c.emptyAcquireCount, prometheus.CounterValue,
+		float64(stat.EmptyAcquireCount()))
+	metrics <- prometheus.MustNewConstMetric(c.canceledAcquireCount, prometheus.CounterValue,
+		float64(stat.CanceledAcquireCount()))
+	metrics <- prometheus.MustNewConstMetric(c.acquireDuration, prometheus.CounterValue,
+		stat.AcquireDuration().Seconds())
+}
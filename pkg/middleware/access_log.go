@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"github.com/SanExpett/auto-catalog/internal/server/delivery"
+	"github.com/SanExpett/auto-catalog/pkg/my_logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"net/http"
+	"time"
+)
+
+const (
+	HeaderRequestID = "X-Request-ID"
+
+	maxLenRequestID = 128
+)
+
+// AccessLog accepts X-Request-ID of client or generates it, puts logger with request id into context
+// and writes one line per request. Requests without server errors are logged at successLevel,
+// so frequent probes can stay below info level.
+func AccessLog(logger *zap.SugaredLogger, successLevel zapcore.Level, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		requestID := r.Header.Get(HeaderRequestID)
+		if !isValidRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		w.Header().Set(HeaderRequestID, requestID)
+
+		requestLogger := logger.With("request_id", requestID)
+		recorder := newResponseWriter(w)
+
+		ctx := my_logger.WithContext(delivery.WithRouteHolder(r.Context()), requestLogger)
+		r = r.WithContext(ctx)
+
+		next.ServeHTTP(recorder, r)
+
+		level := successLevel
+		if recorder.status >= http.StatusInternalServerError {
+			level = zapcore.ErrorLevel
+		}
+
+		requestLogger.Logw(level, "access",
+			"method", r.Method,
+			"route", delivery.RouteFromContext(ctx),
+			"path", r.URL.Path,
+			"status", recorder.status,
+			"bytes", recorder.bytes,
+			"duration", time.Since(start),
+			"client_ip", delivery.ClientIP(r),
+		)
+	})
+}
+
+// isValidRequestID rejects ids which are too long or could break lines of logs.
+func isValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxLenRequestID {
+		return false
+	}
+
+	for _, symbol := range requestID {
+		if symbol < '!' || symbol > '~' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"github.com/SanExpett/auto-catalog/internal/server/delivery"
+	"github.com/SanExpett/auto-catalog/pkg/access"
+	"github.com/SanExpett/auto-catalog/pkg/models"
+	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
+	"github.com/SanExpett/auto-catalog/pkg/my_logger"
+	"net/http"
+	"strings"
+)
+
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, key string) (*models.APIKey, error)
+}
+
+type SessionAuthenticator interface {
+	AuthenticateSession(ctx context.Context, token string) (*models.User, error)
+}
+
+// Auth puts into context API key of header "Authorization: Bearer <key>" or user of session cookie,
+// API key is unrestricted by access.WithUnrestricted, user linked to person is restricted to data of that person
+// by access.WithOwner and user not linked to person has no access to data at all.
+// Invalid key is rejected, invalid session cookie is removed. Requests without them go further anonymously,
+// scopes of routes are checked by router.
+func Auth(apiKeys APIKeyAuthenticator, sessions SessionAuthenticator, secureCookie bool, next http.Handler,
+) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		if authorization := r.Header.Get("Authorization"); authorization != "" {
+			key, ok := strings.CutPrefix(authorization, "Bearer ")
+			if !ok || key == "" {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				delivery.SendErrResponse(w, r, my_logger.FromContext(ctx), delivery.ErrUnauthorized)
+
+				return
+			}
+
+			apiKey, err := apiKeys.Authenticate(ctx, key)
+			if err != nil {
+				if isUnauthorized(err) {
+					w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				} else {
+					my_logger.FromContext(ctx).Errorf("in Auth: %+v", err)
+				}
+
+				delivery.HandleErr(w, r, my_logger.FromContext(ctx), err)
+
+				return
+			}
+
+			ctx = access.WithUnrestricted(delivery.WithAPIKey(ctx, apiKey))
+			ctx = my_logger.WithContext(ctx, my_logger.FromContext(ctx).With("api_key_id", apiKey.ID))
+			next.ServeHTTP(w, r.WithContext(ctx))
+
+			return
+		}
+
+		if cookie, err := r.Cookie(delivery.CookieAuthName); err == nil && cookie.Value != "" {
+			user, err := sessions.AuthenticateSession(ctx, cookie.Value)
+			switch {
+			case err == nil:
+				ctx = delivery.WithUser(ctx, user)
+				ctx = my_logger.WithContext(ctx, my_logger.FromContext(ctx).With("user_id", user.ID))
+
+				if user.PersonID != nil {
+					ctx = access.WithOwner(ctx, *user.PersonID)
+				}
+			case isUnauthorized(err):
+				http.SetCookie(w, delivery.ExpiredAuthCookie(secureCookie))
+			default:
+				my_logger.FromContext(ctx).Errorf("in Auth: %+v", err)
+				delivery.HandleErr(w, r, my_logger.FromContext(ctx), err)
+
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func isUnauthorized(err error) bool {
+	myErr := &myerrors.Error{}
+
+	return errors.As(err, &myErr) && myErr.Status() == http.StatusUnauthorized
+}
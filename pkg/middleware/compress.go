@@ -0,0 +1,226 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"github.com/SanExpett/auto-catalog/pkg/my_logger"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	encodingGzip    = "gzip"
+	encodingDeflate = "deflate"
+)
+
+var gzipWriters = sync.Pool{ //nolint:gochecknoglobals
+	New: func() any {
+		return gzip.NewWriter(io.Discard)
+	},
+}
+
+// compressWriter buffers beginning of response and compresses it only if it grows to minSize,
+// small responses are not worth compression.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	minSize  int
+	// ifNoneMatch is original header of request, 304 response keeps etag of compressed representation if
+	// client has it
+	ifNoneMatch string
+
+	status  int
+	buf     []byte
+	decided bool
+	encoder io.WriteCloser
+}
+
+func (w *compressWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if w.decided {
+		if w.encoder != nil {
+			return w.encoder.Write(b) //nolint:wrapcheck
+		}
+
+		return w.ResponseWriter.Write(b) //nolint:wrapcheck
+	}
+
+	w.buf = append(w.buf, b...)
+	if len(w.buf) >= w.minSize {
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(b), nil
+}
+
+// decide sends headers and buffered beginning of response, compressed if compress is set and response
+// may be compressed.
+func (w *compressWriter) decide(compress bool) error {
+	w.decided = true
+
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	header := w.Header()
+	etag := header.Get("ETag")
+
+	switch {
+	case w.status == http.StatusNotModified:
+		if etag != "" && strings.Contains(w.ifNoneMatch, w.encodedETag(etag)) {
+			header.Set("ETag", w.encodedETag(etag))
+		}
+	case compress && header.Get("Content-Encoding") == "" && w.status != http.StatusNoContent:
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+
+		// representation differs from uncompressed one, so does its strong etag
+		if etag != "" {
+			header.Set("ETag", w.encodedETag(etag))
+		}
+
+		w.encoder = w.newEncoder()
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	var err error
+	if w.encoder != nil {
+		_, err = w.encoder.Write(w.buf)
+	} else {
+		_, err = w.ResponseWriter.Write(w.buf)
+	}
+
+	w.buf = nil
+
+	return err //nolint:wrapcheck
+}
+
+func (w *compressWriter) encodedETag(etag string) string {
+	return strings.TrimSuffix(etag, `"`) + "-" + w.encoding + `"`
+}
+
+func (w *compressWriter) newEncoder() io.WriteCloser {
+	// deflate coding of HTTP is zlib stream, not raw deflate data
+	if w.encoding == encodingDeflate {
+		return zlib.NewWriter(w.ResponseWriter)
+	}
+
+	encoder, _ := gzipWriters.Get().(*gzip.Writer)
+	encoder.Reset(w.ResponseWriter)
+
+	return encoder
+}
+
+// close sends rest of response, response which didn't grow to minSize is sent as is.
+func (w *compressWriter) close() error {
+	if !w.decided {
+		// handler which wrote nothing leaves status to net/http
+		if w.status == 0 && len(w.buf) == 0 {
+			return nil
+		}
+
+		return w.decide(false)
+	}
+
+	if w.encoder == nil {
+		return nil
+	}
+
+	err := w.encoder.Close()
+
+	if encoder, ok := w.encoder.(*gzip.Writer); ok {
+		gzipWriters.Put(encoder)
+	}
+
+	return err //nolint:wrapcheck
+}
+
+// Unwrap lets http.ResponseController reach underlying writer.
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Compress compresses responses of at least minSize bytes by gzip or deflate accepted by client.
+// Compressed responses have etags with suffix of encoding, it is removed from If-None-Match of requests.
+func Compress(minSize int, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := acceptedEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		ifNoneMatch := r.Header.Get("If-None-Match")
+		if ifNoneMatch != "" {
+			r = r.Clone(r.Context())
+			r.Header.Set("If-None-Match", strings.ReplaceAll(ifNoneMatch, "-"+encoding+`"`, `"`))
+		}
+
+		writer := &compressWriter{ResponseWriter: w, encoding: encoding, minSize: minSize, ifNoneMatch: ifNoneMatch,
+			status: 0, buf: nil, decided: false, encoder: nil}
+
+		defer func() {
+			if err := writer.close(); err != nil {
+				my_logger.FromContext(r.Context()).Warnf("in Compress: %+v", err)
+			}
+		}()
+
+		next.ServeHTTP(writer, r)
+	})
+}
+
+// acceptedEncoding chooses gzip or deflate from Accept-Encoding header, gzip is preferred at equal weights.
+func acceptedEncoding(acceptEncoding string) string {
+	var (
+		chosen        string
+		chosenQuality float64
+	)
+
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+
+		if coding != encodingGzip && coding != encodingDeflate {
+			continue
+		}
+
+		quality := 1.0
+
+		if rawQuality, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(rawQuality, 64)
+			if err != nil {
+				continue
+			}
+
+			quality = parsed
+		}
+
+		if quality <= 0 {
+			continue
+		}
+
+		if quality > chosenQuality || (quality == chosenQuality && coding == encodingGzip) {
+			chosen, chosenQuality = coding, quality
+		}
+	}
+
+	return chosen
+}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"github.com/SanExpett/auto-catalog/internal/server/delivery"
+	"github.com/SanExpett/auto-catalog/pkg/metrics"
+	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
+	"github.com/SanExpett/auto-catalog/pkg/my_logger"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	ShedReasonQueueFull    = "queue_full"
+	ShedReasonQueueTimeout = "queue_timeout"
+
+	// retryAfterOverloaded is short, spikes which shed requests usually pass quickly
+	retryAfterOverloaded = time.Second
+)
+
+// ConcurrencyLimiter bounds count of requests handled at once. Excess requests wait in short queue
+// and are shed if it is full or they wait too long.
+type ConcurrencyLimiter struct {
+	inFlight     chan struct{}
+	queued       chan struct{}
+	queueTimeout time.Duration
+}
+
+// NewConcurrencyLimiter returns limiter of maxInFlight requests with queue of maxQueued requests waiting
+// at most queueTimeout. If maxInFlight is 0 requests are not limited and nil is returned.
+func NewConcurrencyLimiter(maxInFlight int, maxQueued int, queueTimeout time.Duration) *ConcurrencyLimiter {
+	if maxInFlight == 0 {
+		return nil
+	}
+
+	return &ConcurrencyLimiter{
+		inFlight:     make(chan struct{}, maxInFlight),
+		queued:       make(chan struct{}, maxQueued),
+		queueTimeout: queueTimeout,
+	}
+}
+
+// acquire takes place of request in flight, it returns reason of shedding if request has to be rejected.
+func (l *ConcurrencyLimiter) acquire() (string, bool) {
+	select {
+	case l.inFlight <- struct{}{}:
+		return "", true
+	default:
+	}
+
+	select {
+	case l.queued <- struct{}{}:
+	default:
+		return ShedReasonQueueFull, false
+	}
+
+	defer func() { <-l.queued }()
+
+	timer := time.NewTimer(l.queueTimeout)
+	defer timer.Stop()
+
+	select {
+	case l.inFlight <- struct{}{}:
+		return "", true
+	case <-timer.C:
+		return ShedReasonQueueTimeout, false
+	}
+}
+
+func (l *ConcurrencyLimiter) release() {
+	<-l.inFlight
+}
+
+// LimitConcurrency sheds requests which limiter can't take with 503 and Retry-After, so that spikes fail fast
+// instead of piling up on pool of connections. Nil limiter doesn't limit requests.
+func LimitConcurrency(limiter *ConcurrencyLimiter, next http.Handler) http.Handler {
+	if limiter == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reason, ok := limiter.acquire()
+		if !ok {
+			metrics.IncShed(reason)
+
+			retryAfter := strconv.Itoa(int(retryAfterOverloaded.Seconds()))
+			w.Header().Set("Retry-After", retryAfter)
+			delivery.SendErrResponse(w, r, my_logger.FromContext(r.Context()),
+				myerrors.NewServiceUnavailableError("overloaded", retryAfter))
+
+			return
+		}
+
+		defer limiter.release()
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -1,23 +1,170 @@
 package middleware
 
-import "net/http"
-
-func setupHeadersCORS(w http.ResponseWriter, allowOrigin string, schema string) {
-	w.Header().Set("Access-Control-Allow-Origin", schema+allowOrigin)
-	w.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE, PATCH")
-	w.Header().Set("Access-Control-Allow-Headers",
-		"Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
-	w.Header().Set("Access-Control-Allow-Credentials", "true")
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	anyOrigin      = "*"
+	wildcardPrefix = "*."
+
+	exposeHeaders = "X-CSRF-Token, RateLimit-Policy, RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset, Retry-After"
+)
+
+var ErrInvalidOrigin = errors.New("origin must be * or [scheme://]host[:port] where host may be *.domain")
+
+// originPattern matches origin exactly or, if it has wildcard, origins with any subdomain between prefix and suffix.
+type originPattern struct {
+	prefix   string
+	suffix   string
+	wildcard bool
+}
+
+func (p originPattern) match(origin string) bool {
+	if !p.wildcard {
+		return origin == p.prefix
+	}
+
+	if len(origin) <= len(p.prefix)+len(p.suffix) || !strings.HasPrefix(origin, p.prefix) ||
+		!strings.HasSuffix(origin, p.suffix) {
+		return false
+	}
+
+	subdomain := origin[len(p.prefix) : len(origin)-len(p.suffix)]
+
+	return !strings.ContainsAny(subdomain, "/:@?#") && !strings.HasPrefix(subdomain, ".") &&
+		!strings.HasSuffix(subdomain, ".")
+}
+
+// CORSPolicy describes which origins may send cross-origin requests and what they may send.
+type CORSPolicy struct {
+	origins      []originPattern
+	anyOrigin    bool
+	allowMethods string
+	allowHeaders string
+	maxAge       string
 }
 
-func SetupCORS(next http.HandlerFunc, addrOrigin string, schema string) http.Handler {
+// NewCORSPolicy parses allowed origins like https://app.example.com or https://*.example.com, origins without
+// scheme are prefixed by defaultScheme and * allows any origin. Preflight responses may be cached for maxAge,
+// zero maxAge leaves it to browser.
+func NewCORSPolicy(origins []string, defaultScheme string, allowMethods []string, allowHeaders []string,
+	maxAge time.Duration,
+) (*CORSPolicy, error) {
+	policy := &CORSPolicy{
+		origins:      make([]originPattern, 0, len(origins)),
+		anyOrigin:    false,
+		allowMethods: strings.Join(allowMethods, ", "),
+		allowHeaders: strings.Join(allowHeaders, ", "),
+		maxAge:       "",
+	}
+
+	if maxAge > 0 {
+		policy.maxAge = strconv.Itoa(int(maxAge.Seconds()))
+	}
+
+	for _, origin := range origins {
+		if origin == anyOrigin {
+			policy.anyOrigin = true
+
+			continue
+		}
+
+		pattern, err := parseOrigin(origin, defaultScheme)
+		if err != nil {
+			return nil, err
+		}
+
+		policy.origins = append(policy.origins, pattern)
+	}
+
+	return policy, nil
+}
+
+func parseOrigin(origin string, defaultScheme string) (originPattern, error) {
+	if !strings.Contains(origin, "://") {
+		origin = defaultScheme + origin
+	}
+
+	origin = strings.ToLower(origin)
+
+	scheme, host, _ := strings.Cut(origin, "://")
+	if scheme == "" || host == "" || strings.ContainsAny(host, "/@?#") {
+		return originPattern{}, fmt.Errorf("%w, got %q", ErrInvalidOrigin, origin) //nolint:exhaustruct
+	}
+
+	host, wildcard := strings.CutPrefix(host, wildcardPrefix)
+	if host == "" || strings.Contains(host, "*") {
+		return originPattern{}, fmt.Errorf("%w, got %q", ErrInvalidOrigin, origin) //nolint:exhaustruct
+	}
+
+	if wildcard {
+		return originPattern{prefix: scheme + "://", suffix: "." + host, wildcard: true}, nil
+	}
+
+	return originPattern{prefix: scheme + "://" + host, suffix: "", wildcard: false}, nil
+}
+
+// listed reports whether origin matches one of origins of policy, such origins may send credentials.
+func (p *CORSPolicy) listed(origin string) bool {
+	for _, pattern := range p.origins {
+		if pattern.match(origin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SetupCORS echoes listed Origin of request with credentials allowed. If policy allows any origin, other origins
+// get literal * without credentials, so no site can send requests with cookie session of user. Origins which
+// aren't allowed get no CORS headers. OPTIONS requests are answered by 204, preflight ones get allowed methods
+// and headers of policy.
+func SetupCORS(policy *CORSPolicy, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		setupHeadersCORS(w, addrOrigin, schema)
+		header := w.Header()
+		header.Add("Vary", "Origin")
+
+		origin := r.Header.Get("Origin")
+		allowed := false
+
+		switch {
+		case origin == "":
+		case policy.listed(origin):
+			allowed = true
+
+			header.Set("Access-Control-Allow-Origin", origin)
+			header.Set("Access-Control-Allow-Credentials", "true")
+		case policy.anyOrigin:
+			allowed = true
+
+			header.Set("Access-Control-Allow-Origin", anyOrigin)
+		}
+
+		if allowed {
+			header.Set("Access-Control-Expose-Headers", exposeHeaders)
+		}
+
+		if r.Method != http.MethodOptions {
+			next.ServeHTTP(w, r)
 
-		if r.Method == http.MethodOptions {
 			return
 		}
 
-		next.ServeHTTP(w, r)
+		if allowed && r.Header.Get("Access-Control-Request-Method") != "" {
+			header.Set("Access-Control-Allow-Methods", policy.allowMethods)
+			header.Set("Access-Control-Allow-Headers", policy.allowHeaders)
+
+			if policy.maxAge != "" {
+				header.Set("Access-Control-Max-Age", policy.maxAge)
+			}
+		}
+
+		w.WriteHeader(http.StatusNoContent)
 	})
 }
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"github.com/SanExpett/auto-catalog/internal/server/delivery"
+	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
+	"github.com/SanExpett/auto-catalog/pkg/my_logger"
+	"net/http"
+)
+
+var (
+	ErrInvalidCSRFToken = myerrors.NewForbiddenError("csrf_invalid")
+)
+
+// CSRF requires header X-CSRF-Token with token of session for unsafe methods of requests authenticated
+// by session cookie. Requests of API keys and anonymous requests are not sent by browser on behalf of user,
+// so they are exempt.
+func CSRF(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		if isSafeMethod(r.Method) || delivery.UserFromContext(ctx) == nil || delivery.APIKeyFromContext(ctx) != nil {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		cookie, err := r.Cookie(delivery.CookieAuthName)
+		presented := r.Header.Get(delivery.HeaderCSRFToken)
+
+		if err != nil || presented == "" ||
+			subtle.ConstantTimeCompare([]byte(presented), []byte(delivery.CSRFToken(cookie.Value))) != 1 {
+			my_logger.FromContext(ctx).Warnf("in CSRF: rejected %s %s", r.Method, r.URL.Path)
+			delivery.SendErrResponse(w, r, my_logger.FromContext(ctx), ErrInvalidCSRFToken)
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,12 @@
+package middleware
+
+import "net/http"
+
+// Deprecation marks responses of deprecated API version and links to its successor.
+func Deprecation(successor string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Link", "<"+successor+`>; rel="successor-version"`)
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,13 @@
+package middleware
+
+import (
+	"github.com/SanExpett/auto-catalog/internal/server/delivery"
+	"net/http"
+)
+
+func ErrorFormat(format delivery.ErrorFormat, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = r.WithContext(delivery.WithErrorFormat(r.Context(), format))
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"github.com/SanExpett/auto-catalog/internal/server/delivery"
+	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
+	"net/http"
+)
+
+// Lang chooses language of response messages from Accept-Language header.
+func Lang(defaultLang myerrors.Lang, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lang := myerrors.LangFromAcceptLanguage(r.Header.Get("Accept-Language"), defaultLang)
+
+		w.Header().Set("Content-Language", string(lang))
+		w.Header().Add("Vary", "Accept-Language")
+
+		r = r.WithContext(delivery.WithLang(r.Context(), lang))
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"github.com/SanExpett/auto-catalog/internal/server/delivery"
+	"github.com/SanExpett/auto-catalog/pkg/metrics"
+	"net/http"
+	"time"
+)
+
+// Metrics counts requests and their latency labeled by pattern of route matched by router inside next.
+func Metrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		done := metrics.IncInFlight()
+
+		defer done()
+
+		recorder := newResponseWriter(w)
+		r = r.WithContext(delivery.WithRouteHolder(r.Context()))
+
+		next.ServeHTTP(recorder, r)
+
+		route := delivery.RouteFromContext(r.Context())
+		if route == "" {
+			route = metrics.RouteUnmatched
+		}
+
+		metrics.ObserveHTTPRequest(route, methodLabel(r.Method), recorder.status, time.Since(start))
+	})
+}
+
+// methodLabel keeps cardinality of method label bounded, clients may send any method.
+func methodLabel(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodOptions:
+		return method
+	default:
+		return "OTHER"
+	}
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"github.com/SanExpett/auto-catalog/internal/server/delivery"
+	"github.com/SanExpett/auto-catalog/pkg/my_logger"
 	"net/http"
 
 	"go.uber.org/zap"
@@ -11,9 +12,8 @@ func Panic(next http.Handler, logger *zap.SugaredLogger) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
 			if err := recover(); err != nil {
-				logger.Errorf("panic recovered: %+v\n", err)
-				delivery.SendErrResponse(w, logger,
-					delivery.NewErrResponse(delivery.StatusErrInternalServer, delivery.ErrInternalServer))
+				my_logger.FromContext(r.Context()).Errorf("panic recovered: %+v\n", err)
+				delivery.SendErrResponse(w, r, logger, delivery.ErrInternalServer)
 			}
 		}()
 		next.ServeHTTP(w, r)
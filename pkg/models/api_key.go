@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+)
+
+// Scope is permission granted to API key.
+type Scope string
+
+const (
+	ScopeCarsRead    Scope = "cars:read"
+	ScopeCarsWrite   Scope = "cars:write"
+	ScopePeopleRead  Scope = "people:read"
+	ScopePeopleWrite Scope = "people:write"
+	// ScopeAdmin grants all other scopes and access to admin endpoints
+	ScopeAdmin Scope = "admin"
+)
+
+// Scopes lists all known scopes.
+var Scopes = []Scope{ScopeCarsRead, ScopeCarsWrite, ScopePeopleRead, ScopePeopleWrite, ScopeAdmin} //nolint:gochecknoglobals
+
+type APIKey struct {
+	ID         uint64     `json:"id"           valid:"required"`
+	Name       string     `json:"name"         valid:"required"`
+	Prefix     string     `json:"prefix"       valid:"required"`
+	Scopes     []Scope    `json:"scopes"       valid:"required"`
+	CreatedAt  time.Time  `json:"created_at"   valid:"required"`
+	LastUsedAt *time.Time `json:"last_used_at" valid:"optional"`
+	RevokedAt  *time.Time `json:"revoked_at"   valid:"optional"`
+}
+
+// HasScope reports whether key is granted scope, nil key has no scopes.
+func (k *APIKey) HasScope(scope Scope) bool {
+	if k == nil {
+		return false
+	}
+
+	for _, granted := range k.Scopes {
+		if granted == scope || granted == ScopeAdmin {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsKnownScope reports whether scope is one of Scopes.
+func IsKnownScope(scope Scope) bool {
+	for _, known := range Scopes {
+		if known == scope {
+			return true
+		}
+	}
+
+	return false
+}
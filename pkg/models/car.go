@@ -28,7 +28,7 @@ func init() {
 		}
 
 		if year, ok := i.(uint64); ok {
-			return year > minCarYear
+			return year >= minCarYear
 		}
 
 		return false
@@ -45,11 +45,12 @@ type Car struct {
 	CreatedAt time.Time `json:"created_at"  valid:"required"`
 }
 
+// PreCar is validated as constraints of table car check it, lengths are counted in characters as there.
 type PreCar struct {
 	OwnerID uint64 `json:"owner_id"    valid:"required"`
 	RegNum  string `json:"reg_num"     valid:"required,regNumCheck"`
-	Mark    string `json:"mark"        valid:"required"`
-	Model   string `json:"model"       valid:"required"`
+	Mark    string `json:"mark"        valid:"required,runelength(1|256)"`
+	Model   string `json:"model"       valid:"required,runelength(1|256)"`
 	Year    uint64 `json:"year"        valid:"optional,yearCheck"`
 }
 
@@ -14,10 +14,11 @@ type People struct {
 	CreatedAt  time.Time `json:"created_at"  valid:"required"`
 }
 
+// PrePeople is validated as constraints of table people check it, lengths are counted in characters as there.
 type PrePeople struct {
-	Name       string `json:"name"        valid:"required"`
-	Surname    string `json:"surname"     valid:"required"`
-	Patronymic string `json:"patronymic"  valid:"optional"`
+	Name       string `json:"name"        valid:"required,runelength(1|64)"`
+	Surname    string `json:"surname"     valid:"required,runelength(1|64)"`
+	Patronymic string `json:"patronymic"  valid:"optional,runelength(0|64)"`
 }
 
 func (p *PrePeople) Trim() {
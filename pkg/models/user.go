@@ -0,0 +1,78 @@
+package models
+
+import (
+	"github.com/asaskevich/govalidator"
+	"strings"
+	"time"
+)
+
+const (
+	minPasswordLen = 8
+	// maxPasswordLen is limit of bcrypt, longer passwords are truncated by it silently
+	maxPasswordLen = 72
+)
+
+// UserScopes are scopes granted to users authenticated by session. Anyone may register, so users only read,
+// data is changed by API keys.
+var UserScopes = []Scope{ScopeCarsRead, ScopePeopleRead} //nolint:gochecknoglobals
+
+// OwnerScopes are scopes of users linked to person by admin, services restrict them to data of that person.
+var OwnerScopes = []Scope{ScopeCarsRead, ScopeCarsWrite, ScopePeopleRead, ScopePeopleWrite} //nolint:gochecknoglobals
+
+func init() {
+	govalidator.CustomTypeTagMap.Set("passwordCheck", func(i interface{}, o interface{}) bool {
+		if password, ok := i.(string); ok {
+			return len(password) >= minPasswordLen && len(password) <= maxPasswordLen
+		}
+
+		return false
+	})
+}
+
+type User struct {
+	ID    uint64 `json:"id"          valid:"required"`
+	Email string `json:"email"       valid:"required"`
+	// PersonID links user to person, such user is owner who may see only this person and cars of that person
+	PersonID  *uint64   `json:"person_id,omitempty" valid:"optional"`
+	CreatedAt time.Time `json:"created_at"  valid:"required"`
+}
+
+type PreUser struct {
+	Email    string `json:"email"       valid:"required,email"`
+	Password string `json:"password"    valid:"required,passwordCheck"`
+}
+
+// Session is server side state of logged in user. Token is known only right after login, storage keeps its hash.
+type Session struct {
+	ID         uint64    `json:"id"`
+	UserID     uint64    `json:"user_id"`
+	Token      string    `json:"-"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// Trim normalizes email, password is kept as is.
+func (u *PreUser) Trim() {
+	u.Email = strings.ToLower(strings.TrimSpace(u.Email))
+}
+
+// HasScope reports whether user is granted scope, nil user has no scopes.
+func (u *User) HasScope(scope Scope) bool {
+	if u == nil {
+		return false
+	}
+
+	scopes := UserScopes
+	if u.PersonID != nil {
+		scopes = OwnerScopes
+	}
+
+	for _, granted := range scopes {
+		if granted == scope {
+			return true
+		}
+	}
+
+	return false
+}
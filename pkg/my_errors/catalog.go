@@ -0,0 +1,159 @@
+package my_errors
+
+import "fmt"
+
+var catalogs = map[Lang]map[string]string{ //nolint:gochecknoglobals
+	LangRu: {
+		"internal_server_error": "Ошибка на сервере",
+		"cookie_not_presented":  "Должна быть выставлена cookie, а её нет",
+		"wrong_number_param":    "Получили некорректный числовой параметр. Он должен быть целым %s=%s",
+		"validation_failed":     "Некорректные значения полей",
+		"constraint_violation":  "нарушено ограничение %s",
+		"route_not_found":       "Такого метода API не существует",
+		"method_not_allowed":    "HTTP метод не поддерживается этим методом API",
+		"unauthorized":          "Требуется авторизация",
+		"forbidden":             "Недостаточно прав, нужно разрешение %s",
+		"api_key_invalid":       "API ключ неверный или отозван",
+		"rate_limited":          "Слишком много запросов, повторите через %s с",
+		"overloaded":            "Сервер перегружен, повторите через %s с",
+
+		"field_required":     "обязательное поле",
+		"field_invalid":      "некорректное значение: %s",
+		"field_invalid_type": "ожидался тип %s",
+		"reg_num_format":     "гос. номер должен быть в формате А123ВС777",
+		"year_out_of_range":  "год должен быть не меньше 1885",
+
+		"car_not_found":              "Эта машина не найдена",
+		"car_not_updated":            "Не получилось обновить данные автомобиля",
+		"car_no_update_fields":       "Вы пытаетесь обновить пустое количество полей автомобиля",
+		"car_owner_not_found":        "Вы пытаетесь добавить машину для несуществующего человека",
+		"car_decode":                 "Некорректный json машины",
+		"car_deleted":                "Автомобиль успешно удален",
+		"reg_num_already_registered": "этот гос. номер уже зарегистрирован",
+		"reg_num_empty":              "гос. номер не может быть пустым",
+		"reg_num_length":             "гос. номер должен состоять из 9 символов",
+		"mark_empty":                 "марка не может быть пустой",
+		"mark_too_long":              "марка должна быть не длиннее 256 символов",
+		"model_empty":                "модель не может быть пустой",
+		"model_too_long":             "модель должна быть не длиннее 256 символов",
+		"owner_not_exists":           "человека с таким id не существует",
+
+		"person_not_found":    "Этот человек не найден",
+		"person_not_updated":  "Не получилось обновить данные человека",
+		"person_decode":       "Некорректный json человека",
+		"person_deleted":      "Человек успешно удален",
+		"name_empty":          "имя не может быть пустым",
+		"name_too_long":       "имя должно быть не длиннее 64 символов",
+		"surname_empty":       "фамилия не может быть пустой",
+		"surname_too_long":    "фамилия должна быть не длиннее 64 символов",
+		"patronymic_too_long": "отчество должно быть не длиннее 64 символов",
+
+		"log_level_decode":         "Некорректный json уровня логов",
+		"log_level_invalid":        "Некорректный уровень логов %s, допустимы debug, info, warn, error",
+		"rate_limit_decode":        "Некорректный json лимитов запросов",
+		"rate_limit_invalid":       "некорректный лимит %s, ожидается вида 100/1m или off",
+		"rate_limit_class_unknown": "неизвестный класс запросов, допустимы read, write",
+
+		"api_key_not_found": "API ключ не найден",
+		"scope_unknown":     "неизвестное разрешение %s",
+
+		"user_not_found":           "Пользователь не найден",
+		"user_decode":              "Некорректный json пользователя",
+		"invalid_credentials":      "Неверный email или пароль",
+		"session_invalid":          "Сессия не найдена или истекла, войдите снова",
+		"logged_out":               "Вы вышли из аккаунта",
+		"email_format":             "некорректный email",
+		"email_too_long":           "email должен быть не длиннее 256 символов",
+		"email_already_registered": "этот email уже зарегистрирован",
+		"password_length":          "пароль должен быть длиной от 8 до 72 байт",
+		"csrf_invalid":             "Нет CSRF токена или он неверный, получите его в /api/v2/auth/csrf",
+		"person_already_linked":    "этот человек уже связан с другим пользователем",
+		"owner_forbidden":          "Владельцу доступны только просмотр своих данных и изменение марки, модели и года своих машин",
+		"car_field_forbidden":      "Владелец не может изменять поле %s",
+	},
+	LangEn: {
+		"internal_server_error": "Internal server error",
+		"cookie_not_presented":  "Cookie must be set, but it is missing",
+		"wrong_number_param":    "Got invalid numeric parameter. It must be an integer %s=%s",
+		"validation_failed":     "Invalid field values",
+		"constraint_violation":  "constraint %s is violated",
+		"route_not_found":       "API route does not exist",
+		"method_not_allowed":    "HTTP method is not allowed for this API route",
+		"unauthorized":          "Authorization is required",
+		"forbidden":             "Permission %s is required",
+		"api_key_invalid":       "API key is invalid or revoked",
+		"rate_limited":          "Too many requests, retry in %s s",
+		"overloaded":            "Server is overloaded, retry in %s s",
+
+		"field_required":     "field is required",
+		"field_invalid":      "invalid value: %s",
+		"field_invalid_type": "expected type %s",
+		"reg_num_format":     "registration number must match format A123BC777",
+		"year_out_of_range":  "year must be at least 1885",
+
+		"car_not_found":              "Car not found",
+		"car_not_updated":            "Failed to update car data",
+		"car_no_update_fields":       "There are no car fields to update",
+		"car_owner_not_found":        "You are trying to add a car for a person who does not exist",
+		"car_decode":                 "Invalid car json",
+		"car_deleted":                "Car deleted successfully",
+		"reg_num_already_registered": "registration number is already registered",
+		"reg_num_empty":              "registration number must not be empty",
+		"reg_num_length":             "registration number must be 9 characters long",
+		"mark_empty":                 "mark must not be empty",
+		"mark_too_long":              "mark must be at most 256 characters long",
+		"model_empty":                "model must not be empty",
+		"model_too_long":             "model must be at most 256 characters long",
+		"owner_not_exists":           "person with this id does not exist",
+
+		"person_not_found":    "Person not found",
+		"person_not_updated":  "Failed to update person data",
+		"person_decode":       "Invalid person json",
+		"person_deleted":      "Person deleted successfully",
+		"name_empty":          "name must not be empty",
+		"name_too_long":       "name must be at most 64 characters long",
+		"surname_empty":       "surname must not be empty",
+		"surname_too_long":    "surname must be at most 64 characters long",
+		"patronymic_too_long": "patronymic must be at most 64 characters long",
+
+		"log_level_decode":         "Invalid log level json",
+		"log_level_invalid":        "Invalid log level %s, allowed are debug, info, warn, error",
+		"rate_limit_decode":        "Invalid json of rate limits",
+		"rate_limit_invalid":       "invalid limit %s, expected like 100/1m or off",
+		"rate_limit_class_unknown": "unknown class of requests, allowed are read, write",
+
+		"api_key_not_found": "API key not found",
+		"scope_unknown":     "unknown permission %s",
+
+		"user_not_found":           "User not found",
+		"user_decode":              "Invalid user json",
+		"invalid_credentials":      "Invalid email or password",
+		"session_invalid":          "Session is not found or expired, log in again",
+		"logged_out":               "Logged out successfully",
+		"email_format":             "invalid email",
+		"email_too_long":           "email must be at most 256 characters long",
+		"email_already_registered": "this email is already registered",
+		"password_length":          "password must be 8 to 72 bytes long",
+		"csrf_invalid":             "CSRF token is missing or invalid, get it at /api/v2/auth/csrf",
+		"person_already_linked":    "this person is already linked to another user",
+		"owner_forbidden":          "Owner may only view their own data and change mark, model and year of their cars",
+		"car_field_forbidden":      "Owner may not change field %s",
+	},
+}
+
+// Message returns message for key in lang. It falls back to DefaultLang and then to key itself.
+func Message(lang Lang, key string, args ...any) string {
+	format, ok := catalogs[lang][key]
+	if !ok {
+		format, ok = catalogs[DefaultLang][key]
+		if !ok {
+			return key
+		}
+	}
+
+	if len(args) == 0 {
+		return format
+	}
+
+	return fmt.Sprintf(format, args...)
+}
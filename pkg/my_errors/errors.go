@@ -1,19 +1,145 @@
 package my_errors
 
-import "fmt"
+import (
+	"net/http"
+	"strings"
+)
 
 const (
 	ErrTemplate = "%w"
 )
 
+// Error is error shown to client. Key is stable machine-readable code of error and
+// key of message in catalogs, args are arguments of message format.
 type Error struct {
-	err string
+	key    string
+	args   []any
+	status int
+}
+
+func NewError(key string, args ...any) *Error {
+	return &Error{key: key, args: args, status: http.StatusBadRequest}
+}
+
+func NewUnauthorizedError(key string, args ...any) *Error {
+	return &Error{key: key, args: args, status: http.StatusUnauthorized}
+}
+
+func NewForbiddenError(key string, args ...any) *Error {
+	return &Error{key: key, args: args, status: http.StatusForbidden}
+}
+
+func NewNotFoundError(key string, args ...any) *Error {
+	return &Error{key: key, args: args, status: http.StatusNotFound}
+}
+
+func NewMethodNotAllowedError(key string, args ...any) *Error {
+	return &Error{key: key, args: args, status: http.StatusMethodNotAllowed}
+}
+
+func NewConflictError(key string, args ...any) *Error {
+	return &Error{key: key, args: args, status: http.StatusConflict}
+}
+
+func NewTooManyRequestsError(key string, args ...any) *Error {
+	return &Error{key: key, args: args, status: http.StatusTooManyRequests}
+}
+
+func NewServiceUnavailableError(key string, args ...any) *Error {
+	return &Error{key: key, args: args, status: http.StatusServiceUnavailable}
 }
 
-func NewError(format string, args ...any) *Error {
-	return &Error{fmt.Sprintf(format, args...)}
+func NewValidationError(key string, args ...any) *Error {
+	return &Error{key: key, args: args, status: http.StatusUnprocessableEntity}
+}
+
+func NewInternalError(key string, args ...any) *Error {
+	return &Error{key: key, args: args, status: http.StatusInternalServerError}
 }
 
 func (e *Error) Error() string {
+	return Message(DefaultLang, e.key, e.args...)
+}
+
+// Status returns http status code which should be sent to client for this error.
+func (e *Error) Status() int {
+	return e.status
+}
+
+func (e *Error) Code() string {
+	return e.key
+}
+
+func (e *Error) Localize(lang Lang) string {
+	return Message(lang, e.key, e.args...)
+}
+
+// ConstraintError is Error caused by violation of storage constraint on Field.
+type ConstraintError struct {
+	err        *Error
+	Field      string
+	Constraint string
+}
+
+func NewConstraintError(err *Error, field string, constraint string) *ConstraintError {
+	return &ConstraintError{err: err, Field: field, Constraint: constraint}
+}
+
+func (e *ConstraintError) Error() string {
+	return e.Field + ": " + e.err.Error()
+}
+
+func (e *ConstraintError) Unwrap() error {
 	return e.err
 }
+
+func (e *ConstraintError) FieldErrors() []FieldError {
+	return []FieldError{NewFieldError(e.Field, e.Constraint, e.err.key, e.err.args...)}
+}
+
+// FieldError describes why value of one input field is invalid.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+
+	key  string
+	args []any
+}
+
+func NewFieldError(field string, code string, key string, args ...any) FieldError {
+	return FieldError{Field: field, Code: code, Message: Message(DefaultLang, key, args...), key: key, args: args}
+}
+
+func (f FieldError) Localize(lang Lang) FieldError {
+	f.Message = Message(lang, f.key, f.args...)
+
+	return f
+}
+
+// ValidationErrors aggregates errors of all invalid fields of input model.
+type ValidationErrors struct {
+	err    *Error
+	fields []FieldError
+}
+
+func NewValidationErrors(fields []FieldError) *ValidationErrors {
+	return &ValidationErrors{err: NewValidationError("validation_failed"), fields: fields}
+}
+
+func (e *ValidationErrors) Error() string {
+	messages := make([]string, 0, len(e.fields))
+	for _, field := range e.fields {
+		messages = append(messages, field.Field+": "+field.Message)
+	}
+
+	return e.err.Error() + ": " + strings.Join(messages, "; ")
+}
+
+func (e *ValidationErrors) Unwrap() error {
+	return e.err
+}
+
+func (e *ValidationErrors) FieldErrors() []FieldError {
+	return e.fields
+}
@@ -0,0 +1,84 @@
+package my_errors
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Lang string
+
+const (
+	LangRu Lang = "ru"
+	LangEn Lang = "en"
+
+	DefaultLang = LangRu
+)
+
+var ErrUnsupportedLang = errors.New("unsupported language")
+
+// ParseLang returns supported language by its name, for example "en" or "en-US".
+func ParseLang(name string) (Lang, bool) {
+	primary, _, _ := strings.Cut(strings.TrimSpace(name), "-")
+	lang := Lang(strings.ToLower(primary))
+
+	if _, ok := catalogs[lang]; !ok {
+		return "", false
+	}
+
+	return lang, true
+}
+
+// UnmarshalText accepts only supported languages, so Lang can be read from config.
+func (l *Lang) UnmarshalText(text []byte) error {
+	lang, ok := ParseLang(string(text))
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnsupportedLang, text)
+	}
+
+	*l = lang
+
+	return nil
+}
+
+// LangFromAcceptLanguage chooses supported language with the highest weight from
+// Accept-Language header value or returns defaultLang.
+func LangFromAcceptLanguage(acceptLanguage string, defaultLang Lang) Lang {
+	type weightedLang struct {
+		lang   Lang
+		weight float64
+	}
+
+	var langs []weightedLang
+
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		name, params, _ := strings.Cut(part, ";")
+
+		weight := 1.0
+
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+
+			weight = parsed
+		}
+
+		if lang, ok := ParseLang(name); ok && weight > 0 {
+			langs = append(langs, weightedLang{lang: lang, weight: weight})
+		}
+	}
+
+	if len(langs) == 0 {
+		return defaultLang
+	}
+
+	sort.SliceStable(langs, func(i, j int) bool {
+		return langs[i].weight > langs[j].weight
+	})
+
+	return langs[0].lang
+}
@@ -1,28 +1,56 @@
 package my_logger
 
 import (
+	"context"
 	"fmt"
 	"sync"
 
 	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	EncodingJSON    = "json"
+	EncodingConsole = "console"
 )
 
 var (
-	logger *zap.SugaredLogger //nolint:gochecknoglobals
-	once   sync.Once          //nolint:gochecknoglobals
+	logger *zap.SugaredLogger     //nolint:gochecknoglobals
+	level  = zap.NewAtomicLevel() //nolint:gochecknoglobals
+	once   sync.Once              //nolint:gochecknoglobals
 
-	ErrNoLogger = fmt.Errorf("my_logger.Get для отсутствующего логгера")
+	ErrNoLogger        = fmt.Errorf("my_logger.Get для отсутствующего логгера")
+	ErrUnknownEncoding = fmt.Errorf("неизвестная кодировка логов, должна быть json или console")
 )
 
-func New(outputPaths []string, errorOutputPaths []string, options ...zap.Option) (*zap.SugaredLogger, error) {
+type Config struct {
+	Level            zapcore.Level
+	Encoding         string
+	Sampling         bool
+	Caller           bool
+	OutputPaths      []string
+	ErrorOutputPaths []string
+}
+
+func New(config Config, options ...zap.Option) (*zap.SugaredLogger, error) {
 	var err error
 
 	once.Do(func() {
-		cfg := zap.NewProductionConfig()
-		cfg.OutputPaths = outputPaths
-		cfg.ErrorOutputPaths = errorOutputPaths
+		if innerErr := registerRotateSink(); innerErr != nil {
+			err = innerErr
+
+			return
+		}
+
+		cfg, innerErr := buildConfig(config)
+		if innerErr != nil {
+			err = innerErr
+
+			return
+		}
+
 		zapLogger, innerErr := cfg.Build(options...)
 		if innerErr != nil {
 			err = innerErr
@@ -39,6 +67,32 @@ func New(outputPaths []string, errorOutputPaths []string, options ...zap.Option)
 	return logger, nil
 }
 
+func buildConfig(config Config) (zap.Config, error) {
+	cfg := zap.NewProductionConfig()
+
+	level.SetLevel(config.Level)
+	cfg.Level = level
+
+	switch config.Encoding {
+	case EncodingJSON:
+	case EncodingConsole:
+		cfg.Encoding = EncodingConsole
+		cfg.EncoderConfig = zap.NewDevelopmentEncoderConfig()
+	default:
+		return cfg, fmt.Errorf("%w: %s", ErrUnknownEncoding, config.Encoding)
+	}
+
+	if !config.Sampling {
+		cfg.Sampling = nil
+	}
+
+	cfg.DisableCaller = !config.Caller
+	cfg.OutputPaths = config.OutputPaths
+	cfg.ErrorOutputPaths = config.ErrorOutputPaths
+
+	return cfg, nil
+}
+
 func Get() (*zap.SugaredLogger, error) {
 	if logger == nil {
 		fmt.Println(ErrNoLogger)
@@ -48,3 +102,33 @@ func Get() (*zap.SugaredLogger, error) {
 
 	return logger, nil
 }
+
+// Level returns level of logger which can be changed at runtime.
+func Level() zapcore.Level {
+	return level.Level()
+}
+
+func SetLevel(newLevel zapcore.Level) {
+	level.SetLevel(newLevel)
+}
+
+type loggerKey struct{}
+
+// WithContext returns ctx carrying request-scoped logger.
+func WithContext(ctx context.Context, logger *zap.SugaredLogger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns request-scoped logger of ctx. Without it global logger is returned,
+// and no-op logger if global one isn't created yet.
+func FromContext(ctx context.Context) *zap.SugaredLogger {
+	if ctxLogger, ok := ctx.Value(loggerKey{}).(*zap.SugaredLogger); ok {
+		return ctxLogger
+	}
+
+	if logger != nil {
+		return logger
+	}
+
+	return zap.NewNop().Sugar()
+}
@@ -0,0 +1,170 @@
+package my_logger
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
+
+	"go.uber.org/zap"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// SchemeRotate is url scheme of rotating file sink, e.g.
+//
+//	rotate:///var/log/backend/logs.json?max_size=100&max_age=30&max_backups=5&compress=true&rotate_every=24h
+//
+// max_size is in megabytes, max_age is in days. Rotated files older than max_age or exceeding
+// max_backups are removed, rotate_every=0 disables rotation by age.
+const SchemeRotate = "rotate"
+
+const (
+	standardMaxSizeMB   = 100
+	standardMaxAgeDays  = 30
+	standardMaxBackups  = 5
+	standardCompress    = true
+	standardRotateEvery = 24 * time.Hour
+)
+
+var (
+	registerRotateOnce sync.Once //nolint:gochecknoglobals
+
+	ErrRotatePath  = fmt.Errorf("путь файла логов в rotate:// должен быть абсолютным")
+	ErrRotateParam = fmt.Errorf("некорректный параметр rotate://")
+)
+
+// rotateSink is zap.Sink writing to file which is rotated by size and by age.
+type rotateSink struct {
+	*lumberjack.Logger
+
+	// written is set by Write, so rotation by age skips files without new logs
+	written atomic.Bool
+	stop    chan struct{}
+	once    sync.Once
+}
+
+func registerRotateSink() error {
+	var err error
+
+	registerRotateOnce.Do(func() {
+		err = zap.RegisterSink(SchemeRotate, newRotateSink)
+	})
+
+	if err != nil {
+		return fmt.Errorf(myerrors.ErrTemplate, err)
+	}
+
+	return nil
+}
+
+func newRotateSink(sinkURL *url.URL) (zap.Sink, error) {
+	if sinkURL.Path == "" || sinkURL.Path[0] != '/' || sinkURL.Host != "" {
+		return nil, fmt.Errorf("%w: %s", ErrRotatePath, sinkURL.String())
+	}
+
+	query := sinkURL.Query()
+
+	maxSize, err := intParam(query, "max_size", standardMaxSizeMB)
+	if err != nil {
+		return nil, err
+	}
+
+	maxAge, err := intParam(query, "max_age", standardMaxAgeDays)
+	if err != nil {
+		return nil, err
+	}
+
+	maxBackups, err := intParam(query, "max_backups", standardMaxBackups)
+	if err != nil {
+		return nil, err
+	}
+
+	compress := standardCompress
+	if value := query.Get("compress"); value != "" {
+		compress, err = strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: compress=%s", ErrRotateParam, value)
+		}
+	}
+
+	rotateEvery := standardRotateEvery
+	if value := query.Get("rotate_every"); value != "" {
+		rotateEvery, err = time.ParseDuration(value)
+		if err != nil || rotateEvery < 0 {
+			return nil, fmt.Errorf("%w: rotate_every=%s", ErrRotateParam, value)
+		}
+	}
+
+	sink := &rotateSink{
+		Logger: &lumberjack.Logger{ //nolint:exhaustruct
+			Filename:   sinkURL.Path,
+			MaxSize:    maxSize,
+			MaxAge:     maxAge,
+			MaxBackups: maxBackups,
+			LocalTime:  false,
+			Compress:   compress,
+		},
+		written: atomic.Bool{},
+		stop:    make(chan struct{}),
+		once:    sync.Once{},
+	}
+
+	if rotateEvery > 0 {
+		go sink.rotateEvery(rotateEvery)
+	}
+
+	return sink, nil
+}
+
+func intParam(query url.Values, name string, defaultValue int) (int, error) {
+	value := query.Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	result, err := strconv.Atoi(value)
+	if err != nil || result < 0 {
+		return 0, fmt.Errorf("%w: %s=%s", ErrRotateParam, name, value)
+	}
+
+	return result, nil
+}
+
+func (s *rotateSink) rotateEvery(period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if s.written.Swap(false) {
+				_ = s.Logger.Rotate()
+			}
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+func (s *rotateSink) Write(p []byte) (int, error) {
+	s.written.Store(true)
+
+	return s.Logger.Write(p) //nolint:wrapcheck
+}
+
+// Sync does nothing, lumberjack writes directly to file without buffering.
+func (s *rotateSink) Sync() error {
+	return nil
+}
+
+func (s *rotateSink) Close() error {
+	s.once.Do(func() {
+		close(s.stop)
+	})
+
+	return s.Logger.Close() //nolint:wrapcheck
+}
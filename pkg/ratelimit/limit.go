@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidLimit = errors.New("limit must be like 100/1m or off")
+
+const off = "off"
+
+// Limit allows Requests per Window to client, unused requests are accumulated up to Requests.
+// Zero Limit doesn't limit requests.
+type Limit struct {
+	Requests int
+	Window   time.Duration
+}
+
+func (l Limit) IsUnlimited() bool {
+	return l.Requests == 0
+}
+
+// rate returns count of requests restored per second.
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Window.Seconds()
+}
+
+func (l Limit) String() string {
+	if l.IsUnlimited() {
+		return off
+	}
+
+	// 1m instead of 1m0s, as limits are usually written
+	window := l.Window.String()
+	if strings.HasSuffix(window, "m0s") {
+		window = strings.TrimSuffix(window, "0s")
+	}
+
+	if strings.HasSuffix(window, "h0m") {
+		window = strings.TrimSuffix(window, "0m")
+	}
+
+	return strconv.Itoa(l.Requests) + "/" + window
+}
+
+func (l Limit) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText parses limit like 100/1m, "off" disables limit.
+func (l *Limit) UnmarshalText(text []byte) error {
+	if string(text) == off {
+		*l = Limit{Requests: 0, Window: 0}
+
+		return nil
+	}
+
+	rawRequests, rawWindow, ok := strings.Cut(string(text), "/")
+	if !ok {
+		return fmt.Errorf("%w, got %q", ErrInvalidLimit, text)
+	}
+
+	requests, err := strconv.Atoi(rawRequests)
+	if err != nil || requests <= 0 {
+		return fmt.Errorf("%w, got %q", ErrInvalidLimit, text)
+	}
+
+	window, err := time.ParseDuration(rawWindow)
+	if err != nil || window <= 0 {
+		return fmt.Errorf("%w, got %q", ErrInvalidLimit, text)
+	}
+
+	*l = Limit{Requests: requests, Window: window}
+
+	return nil
+}
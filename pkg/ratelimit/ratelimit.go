@@ -0,0 +1,159 @@
+// Package ratelimit limits requests of clients by token buckets, limits of classes of requests can be
+// changed at runtime.
+package ratelimit
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+// Class is kind of requests limited together, e.g. reads or writes.
+type Class string
+
+const (
+	ClassRead  Class = "read"
+	ClassWrite Class = "write"
+)
+
+var ErrUnknownClass = errors.New("unknown class of requests")
+
+// Result describes state of bucket after request, it is sent to client in RateLimit headers.
+type Result struct {
+	Allowed   bool
+	Limit     Limit
+	Remaining int
+	// Reset is time until bucket is full again
+	Reset time.Duration
+	// RetryAfter is time until next request is allowed if this one is not
+	RetryAfter time.Duration
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// Limiter keeps bucket per class and client.
+type Limiter struct {
+	mu      sync.Mutex
+	limits  map[Class]Limit
+	buckets map[Class]map[string]*bucket
+	now     func() time.Time
+}
+
+func NewLimiter(limits map[Class]Limit) *Limiter {
+	limiter := &Limiter{
+		mu:      sync.Mutex{},
+		limits:  make(map[Class]Limit, len(limits)),
+		buckets: make(map[Class]map[string]*bucket, len(limits)),
+		now:     time.Now,
+	}
+
+	for class, limit := range limits {
+		limiter.limits[class] = limit
+		limiter.buckets[class] = make(map[string]*bucket)
+	}
+
+	return limiter
+}
+
+// Allow takes token from bucket of client in class. Requests of unknown class or class without limit are allowed.
+func (l *Limiter) Allow(class Class, client string) Result {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limit, ok := l.limits[class]
+	if !ok || limit.IsUnlimited() {
+		return Result{Allowed: true, Limit: limit, Remaining: 0, Reset: 0, RetryAfter: 0}
+	}
+
+	now := l.now()
+
+	clientBucket, ok := l.buckets[class][client]
+	if !ok {
+		clientBucket = &bucket{tokens: float64(limit.Requests), updated: now}
+		l.buckets[class][client] = clientBucket
+	}
+
+	clientBucket.refill(limit, now)
+
+	result := Result{Allowed: clientBucket.tokens >= 1, Limit: limit, Remaining: 0, Reset: 0, RetryAfter: 0}
+	if result.Allowed {
+		clientBucket.tokens--
+	} else {
+		result.RetryAfter = durationOf(1-clientBucket.tokens, limit)
+	}
+
+	result.Remaining = int(math.Floor(clientBucket.tokens))
+	result.Reset = durationOf(float64(limit.Requests)-clientBucket.tokens, limit)
+
+	return result
+}
+
+// Limits returns current limits of classes.
+func (l *Limiter) Limits() map[Class]Limit {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limits := make(map[Class]Limit, len(l.limits))
+	for class, limit := range l.limits {
+		limits[class] = limit
+	}
+
+	return limits
+}
+
+// SetLimit changes limit of class for all clients at once, accumulated tokens are kept within new limit.
+func (l *Limiter) SetLimit(class Class, limit Limit) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.limits[class]; !ok {
+		return ErrUnknownClass
+	}
+
+	l.limits[class] = limit
+
+	return nil
+}
+
+// Cleanup forgets buckets which are full, they are the same as new ones. It should be called periodically.
+func (l *Limiter) Cleanup() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+
+	for class, buckets := range l.buckets {
+		limit := l.limits[class]
+
+		for client, clientBucket := range buckets {
+			if limit.IsUnlimited() {
+				delete(buckets, client)
+
+				continue
+			}
+
+			clientBucket.refill(limit, now)
+
+			if clientBucket.tokens >= float64(limit.Requests) {
+				delete(buckets, client)
+			}
+		}
+	}
+}
+
+func (b *bucket) refill(limit Limit, now time.Time) {
+	b.tokens = math.Min(float64(limit.Requests), b.tokens+now.Sub(b.updated).Seconds()*limit.rate())
+	b.updated = now
+}
+
+func durationOf(tokens float64, limit Limit) time.Duration {
+	if tokens <= 0 {
+		return 0
+	}
+
+	return time.Duration(tokens / limit.rate() * float64(time.Second))
+}
@@ -1,29 +1,27 @@
 package utils
 
 import (
-	"fmt"
 	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
 	mylogger "github.com/SanExpett/auto-catalog/pkg/my_logger"
 	"net/http"
 	"strconv"
 )
 
-var MessageErrWrongNumberParam = "Получили некорректный числовой параметр. " + //nolint:gochecknoglobals
-	"Он должен быть целым"
+const (
+	MessageErrWrongNumberParam = "wrong_number_param"
+)
 
 func ParseUint64FromRequest(r *http.Request, paramName string) (uint64, error) {
-	logger, err := mylogger.Get()
-	if err != nil {
-		return 0, fmt.Errorf(myerrors.ErrTemplate, err)
+	numberStr, ok := PathParam(r, paramName)
+	if !ok {
+		numberStr = r.URL.Query().Get(paramName)
 	}
 
-	numberStr := r.URL.Query().Get(paramName)
-
 	number, err := strconv.ParseUint(numberStr, 10, 64)
 	if err != nil {
-		err := fmt.Errorf("%s %s=%s", MessageErrWrongNumberParam, paramName, numberStr)
+		err := myerrors.NewError(MessageErrWrongNumberParam, paramName, numberStr)
 
-		logger.Errorln(err)
+		mylogger.FromContext(r.Context()).Errorln(err)
 
 		return 0, err
 	}
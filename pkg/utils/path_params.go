@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+)
+
+type pathParamsKey struct{}
+
+func WithPathParams(ctx context.Context, params map[string]string) context.Context {
+	return context.WithValue(ctx, pathParamsKey{}, params)
+}
+
+// PathParam returns parameter of request path, for example id from /api/v2/cars/{id}.
+func PathParam(r *http.Request, name string) (string, bool) {
+	params, ok := r.Context().Value(pathParamsKey{}).(map[string]string)
+	if !ok {
+		return "", false
+	}
+
+	value, ok := params[name]
+
+	return value, ok
+}
@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+
+	myerrors "github.com/SanExpett/auto-catalog/pkg/my_errors"
+	"github.com/asaskevich/govalidator"
+)
+
+const (
+	CodeRequired      = "required"
+	CodeInvalidFormat = "invalid_format"
+	CodeOutOfRange    = "out_of_range"
+	CodeInvalidType   = "invalid_type"
+	CodeInvalid       = "invalid"
+
+	validatorRequired = "required"
+)
+
+type validatorFieldError struct {
+	code string
+	key  string
+	// keyOfField makes message key from field name, when message depends on field, e.g. its maximum length
+	keyOfField func(field string) string
+}
+
+var validatorsFieldErrors = map[string]validatorFieldError{ //nolint:gochecknoglobals
+	validatorRequired: {code: CodeRequired, key: "field_required", keyOfField: nil},
+	"regNumCheck":     {code: CodeInvalidFormat, key: "reg_num_format", keyOfField: nil},
+	"yearCheck":       {code: CodeOutOfRange, key: "year_out_of_range", keyOfField: nil},
+	"email":           {code: CodeInvalidFormat, key: "email_format", keyOfField: nil},
+	"passwordCheck":   {code: CodeOutOfRange, key: "password_length", keyOfField: nil},
+	"runelength": {code: CodeOutOfRange, key: "", keyOfField: func(field string) string {
+		return field + "_too_long"
+	}},
+}
+
+// ValidateStruct returns errors of all invalid fields of structure s sorted by field name.
+// If skipRequired is true, missing required fields are not errors, it is used for partial updates.
+func ValidateStruct(s any, skipRequired bool) []myerrors.FieldError {
+	_, err := govalidator.ValidateStruct(s)
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrors []myerrors.FieldError
+
+	for _, validatorErr := range flattenValidatorErrors(err) {
+		if skipRequired && validatorErr.Validator == validatorRequired {
+			continue
+		}
+
+		fieldError, ok := validatorsFieldErrors[validatorErr.Validator]
+		if !ok {
+			fieldErrors = append(fieldErrors, myerrors.NewFieldError(validatorErr.Name, CodeInvalid,
+				"field_invalid", validatorErr.Err.Error()))
+
+			continue
+		}
+
+		key := fieldError.key
+		if fieldError.keyOfField != nil {
+			key = fieldError.keyOfField(validatorErr.Name)
+		}
+
+		fieldErrors = append(fieldErrors, myerrors.NewFieldError(validatorErr.Name, fieldError.code, key))
+	}
+
+	sort.Slice(fieldErrors, func(i, j int) bool {
+		return fieldErrors[i].Field < fieldErrors[j].Field
+	})
+
+	return fieldErrors
+}
+
+func flattenValidatorErrors(err error) []govalidator.Error {
+	var validatorErrs govalidator.Errors
+	if errors.As(err, &validatorErrs) {
+		var result []govalidator.Error
+		for _, innerErr := range validatorErrs {
+			result = append(result, flattenValidatorErrors(innerErr)...)
+		}
+
+		return result
+	}
+
+	var validatorErr govalidator.Error
+	if errors.As(err, &validatorErr) {
+		return []govalidator.Error{validatorErr}
+	}
+
+	return []govalidator.Error{{Err: err}} //nolint:exhaustruct
+}
+
+// FieldErrorFromJSON returns error of field for json value of wrong type, for example string instead of number.
+func FieldErrorFromJSON(err error) (myerrors.FieldError, bool) {
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) || typeErr.Field == "" {
+		return myerrors.FieldError{}, false //nolint:exhaustruct
+	}
+
+	return myerrors.NewFieldError(typeErr.Field, CodeInvalidType, "field_invalid_type", typeErr.Type.String()), true
+}